@@ -0,0 +1,218 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+//------------------------------------------------------------------------------
+
+// ErrNotNumber is returned when a target is not a number but needs to be for
+// the intended operation.
+var ErrNotNumber = errors.New("not a number")
+
+// BigInt returns the number held by the element as a *big.Int. Returns
+// ErrNotNumber if the element is not a number, or an error if the number is
+// not an integer.
+func (g *Container) BigInt() (*big.Int, error) {
+	r, err := g.BigRat()
+	if err != nil {
+		return nil, err
+	}
+	if !r.IsInt() {
+		return nil, fmt.Errorf("number %v is not an integer", r.RatString())
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// BigFloat returns the number held by the element as a *big.Float. Numbers
+// stored as float64 are converted exactly, and number literals such as those
+// of json.Number are parsed with enough precision to represent all of their
+// digits. Returns ErrNotNumber if the element is not a number.
+func (g *Container) BigFloat() (*big.Float, error) {
+	switch t := g.Data().(type) {
+	case float64:
+		if math.IsInf(t, 0) || math.IsNaN(t) {
+			return nil, ErrNotNumber
+		}
+		return new(big.Float).SetFloat64(t), nil
+	case json.Number:
+		prec := uint(len(t)) * 4
+		if prec < 64 {
+			prec = 64
+		}
+		f, _, err := big.ParseFloat(string(t), 10, prec, big.ToNearestEven)
+		if err != nil {
+			return nil, ErrNotNumber
+		}
+		return f, nil
+	case *big.Float:
+		return new(big.Float).Copy(t), nil
+	}
+	r, err := g.BigRat()
+	if err != nil {
+		return nil, err
+	}
+	prec := uint(r.Num().BitLen() + r.Denom().BitLen())
+	if prec < 64 {
+		prec = 64
+	}
+	return new(big.Float).SetPrec(prec).SetRat(r), nil
+}
+
+// BigRat returns the number held by the element as a *big.Rat. Numbers stored
+// as float64 are interpreted as the shortest decimal that represents them,
+// therefore float64(0.1) is converted to exactly 1/10. Returns ErrNotNumber if
+// the element is not a number.
+func (g *Container) BigRat() (*big.Rat, error) {
+	r, ok := toRat(g.Data())
+	if !ok {
+		return nil, ErrNotNumber
+	}
+	return r, nil
+}
+
+// SetBig sets a number at a path as a json.Number, which encodes the number
+// exactly. The value can be a *big.Int, *big.Float, *big.Rat or any other
+// number type supported by BigRat. Returns an error if the value is a
+// *big.Rat that cannot be written as a finite decimal, such as 1/3.
+func (g *Container) SetBig(value interface{}, hierarchy ...string) (*Container, error) {
+	n, err := bigNumber(value)
+	if err != nil {
+		return nil, err
+	}
+	return g.Set(n, hierarchy...)
+}
+
+// SetBigP sets a number at a path using dot notation, following the same
+// rules as SetBig.
+func (g *Container) SetBigP(value interface{}, path string) (*Container, error) {
+	return g.SetBig(value, DotPathToSlice(path)...)
+}
+
+// Increment adds a delta to the number at a path without loss of precision,
+// where a missing or null value is treated as zero. The delta can be of any
+// number type supported by BigRat, and negative in order to decrement.
+//
+// When the existing value, or the delta if there is no existing value, is a
+// json.Number or big number type the result is stored as a json.Number.
+// Otherwise the result is stored as a float64 if it can be represented exactly
+// and as a json.Number if it cannot, such as when a counter exceeds 2^53.
+//
+// Returns ErrNotNumber if the existing value or the delta is not a number.
+func (g *Container) Increment(delta interface{}, hierarchy ...string) (*Container, error) {
+	sum, ok := toRat(delta)
+	if !ok {
+		return nil, fmt.Errorf("delta: %w", ErrNotNumber)
+	}
+
+	ref := delta
+	if current := g.Search(hierarchy...).Data(); current != nil {
+		c, ok := toRat(current)
+		if !ok {
+			return nil, ErrNotNumber
+		}
+		sum.Add(sum, c)
+		ref = current
+	}
+
+	switch ref.(type) {
+	case json.Number, *big.Int, *big.Float, *big.Rat:
+	default:
+		if f, ok := exactFloat64(sum); ok {
+			return g.Set(f, hierarchy...)
+		}
+	}
+	n, err := bigNumber(sum)
+	if err != nil {
+		return nil, err
+	}
+	return g.Set(n, hierarchy...)
+}
+
+// IncrementP adds a delta to the number at a path using dot notation,
+// following the same rules as Increment.
+func (g *Container) IncrementP(delta interface{}, path string) (*Container, error) {
+	return g.Increment(delta, DotPathToSlice(path)...)
+}
+
+//------------------------------------------------------------------------------
+
+// exactFloat64 returns r as a float64 if the shortest decimal representation of
+// the float64 is exactly r.
+func exactFloat64(r *big.Rat) (float64, bool) {
+	f, _ := r.Float64()
+	if fr, ok := toRat(f); ok && fr.Cmp(r) == 0 {
+		return f, true
+	}
+	return 0, false
+}
+
+// decimalPlaces returns the number of decimal places required to represent r
+// exactly, or -1 if r cannot be represented as a finite decimal.
+func decimalPlaces(r *big.Rat) int {
+	d := new(big.Int).Set(r.Denom())
+	factors := func(factor int64) int {
+		f, q, m := big.NewInt(factor), new(big.Int), new(big.Int)
+		n := 0
+		for {
+			if q.QuoRem(d, f, m); m.Sign() != 0 {
+				return n
+			}
+			d.Set(q)
+			n++
+		}
+	}
+	twos, fives := factors(2), factors(5)
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return -1
+	}
+	if twos > fives {
+		return twos
+	}
+	return fives
+}
+
+// bigNumber converts a number into a json.Number that represents it exactly.
+func bigNumber(v interface{}) (json.Number, error) {
+	if f, ok := v.(*big.Float); ok {
+		if f.IsInf() {
+			return "", errors.New("cannot represent an infinite number in JSON")
+		}
+		return json.Number(f.Text('g', -1)), nil
+	}
+	r, ok := toRat(v)
+	if !ok {
+		return "", ErrNotNumber
+	}
+	places := decimalPlaces(r)
+	if places < 0 {
+		return "", fmt.Errorf("number %v cannot be represented as a finite decimal", r.RatString())
+	}
+	return json.Number(r.FloatString(places)), nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,161 @@
+package gabs
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestBigAccessors(t *testing.T) {
+	c, err := ParseJSON([]byte(`{"f":0.1,"i":42,"s":"str","big":123456789012345678901234567890,"frac":1.25}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := ParseJSONWithOpts([]byte(`{"big":123456789012345678901234567890,"dec":0.1000000000000000000001}`), ParseOptUseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if i, err := c.Path("i").BigInt(); err != nil || i.String() != "42" {
+		t.Errorf("Wrong result: %v, %v", i, err)
+	}
+	if i, err := n.Path("big").BigInt(); err != nil || i.String() != "123456789012345678901234567890" {
+		t.Errorf("Wrong result: %v, %v", i, err)
+	}
+	if _, err := c.Path("frac").BigInt(); err == nil {
+		t.Error("Expected error from non-integer")
+	}
+	if _, err := c.Path("s").BigInt(); err != ErrNotNumber {
+		t.Errorf("Expected ErrNotNumber, received: %v", err)
+	}
+	if _, err := c.Path("missing").BigRat(); err != ErrNotNumber {
+		t.Errorf("Expected ErrNotNumber, received: %v", err)
+	}
+
+	if r, err := c.Path("f").BigRat(); err != nil || r.RatString() != "1/10" {
+		t.Errorf("Wrong result: %v, %v", r, err)
+	}
+	if r, err := n.Path("dec").BigRat(); err != nil || r.FloatString(22) != "0.1000000000000000000001" {
+		t.Errorf("Wrong result: %v, %v", r, err)
+	}
+
+	if f, err := c.Path("frac").BigFloat(); err != nil || f.Text('g', -1) != "1.25" {
+		t.Errorf("Wrong result: %v, %v", f, err)
+	}
+	if f, err := n.Path("dec").BigFloat(); err != nil || f.Text('f', 22) != "0.1000000000000000000001" {
+		t.Errorf("Wrong result: %v, %v", f, err)
+	}
+	if f, err := n.Path("big").BigFloat(); err != nil || f.Text('f', 0) != "123456789012345678901234567890" {
+		t.Errorf("Wrong result: %v, %v", f, err)
+	}
+	if _, err := c.Path("s").BigFloat(); err != ErrNotNumber {
+		t.Errorf("Expected ErrNotNumber, received: %v", err)
+	}
+}
+
+func TestSetBig(t *testing.T) {
+	huge, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+
+	type testCase struct {
+		value  interface{}
+		output string
+	}
+	tests := []testCase{
+		{value: huge, output: `{"n":-123456789012345678901234567890}`},
+		{value: big.NewRat(5, 4), output: `{"n":1.25}`},
+		{value: big.NewRat(-1, 3000000), output: ``},
+		{value: big.NewRat(10, 1), output: `{"n":10}`},
+		{value: new(big.Float).SetFloat64(1e6), output: `{"n":1e+06}`},
+		{value: 0.1, output: `{"n":0.1}`},
+		{value: int64(7), output: `{"n":7}`},
+		{value: "7", output: ``},
+	}
+
+	for i, test := range tests {
+		c := New()
+		_, err := c.SetBigP(test.value, "n")
+		if test.output == "" {
+			if err == nil {
+				t.Errorf("[%d] Expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Unexpected error: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, c.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestIncrement(t *testing.T) {
+	c, err := ParseJSON([]byte(`{"count":9007199254740991,"price":0.1,"s":"x","nested":{"n":null}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = c.IncrementP(0.2, "price"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := 0.3, c.Path("price").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	if _, err = c.IncrementP(1, "count"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := 9007199254740992.0, c.Path("count").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if _, err = c.IncrementP(1, "count"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := json.Number("9007199254740993"), c.Path("count").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if _, err = c.IncrementP(-3, "count"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := json.Number("9007199254740990"), c.Path("count").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	if _, err = c.Increment(5, "nested", "n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Increment(json.Number("1.10"), "new", "counter"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"count":9007199254740990,"nested":{"n":5},"new":{"counter":1.1},"price":0.3,"s":"x"}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	if _, err = c.IncrementP(1, "s"); err != ErrNotNumber {
+		t.Errorf("Expected ErrNotNumber, received: %v", err)
+	}
+	if _, err = c.IncrementP("1", "price"); !errors.Is(err, ErrNotNumber) {
+		t.Errorf("Expected ErrNotNumber, received: %v", err)
+	}
+}
+
+func TestIncrementJSONNumber(t *testing.T) {
+	c, err := ParseJSONWithOpts([]byte(`{"balance":99999999999999999999.99}`), ParseOptUseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.IncrementP(json.Number("0.01"), "balance"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"balance":100000000000000000000}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if _, err = c.IncrementP(big.NewInt(-1), "balance"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"balance":99999999999999999999}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
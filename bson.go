@@ -0,0 +1,1020 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+
+// BSONObjectID is a BSON ObjectId.
+type BSONObjectID [12]byte
+
+// String returns the hex encoding of the ObjectId.
+func (o BSONObjectID) String() string {
+	return hex.EncodeToString(o[:])
+}
+
+// MarshalText returns the hex encoding of the ObjectId.
+func (o BSONObjectID) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// BSONDecimal128 is a BSON 128-bit decimal floating point value stored in the
+// IEEE 754-2008 binary integer decimal encoding.
+type BSONDecimal128 struct {
+	H, L uint64
+}
+
+// MarshalText returns the string representation of the decimal.
+func (d BSONDecimal128) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// BSONBinary is BSON binary data along with its subtype.
+type BSONBinary struct {
+	Subtype byte
+	Data    []byte
+}
+
+// BSONTimestamp is a BSON timestamp, which is used internally by MongoDB.
+type BSONTimestamp struct {
+	T uint32
+	I uint32
+}
+
+// BSONRegex is a BSON regular expression.
+type BSONRegex struct {
+	Pattern string
+	Options string
+}
+
+// BSONJavaScript is BSON JavaScript code.
+type BSONJavaScript string
+
+// BSONMinKey is the BSON min key, which compares lower than all other values.
+type BSONMinKey struct{}
+
+// BSONMaxKey is the BSON max key, which compares higher than all other values.
+type BSONMaxKey struct{}
+
+// ParseBSON decodes a BSON document into a *Container. Embedded documents and
+// arrays are decoded into map[string]interface{} and []interface{}.
+//
+// Doubles are decoded as float64, int32 and int64 as their respective Go types
+// and datetimes as time.Time in UTC. ObjectIds, decimals, binary data,
+// timestamps, regular expressions, JavaScript code and min/max keys are
+// decoded as their respective BSON types from this package. The deprecated
+// undefined type is decoded as nil and symbols as strings.
+func ParseBSON(sample []byte) (*Container, error) {
+	d := bsonDecoder{b: sample}
+	doc, err := d.decodeDocument(false)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(sample) {
+		return nil, fmt.Errorf("bson: unexpected trailing data at offset %v", d.pos)
+	}
+	return &Container{object: doc}, nil
+}
+
+// BSON encodes an object element as a BSON document. Object keys are sorted
+// in order for the output to be deterministic.
+//
+// Values of type float64 are encoded as doubles, int32 and int64 as their
+// respective BSON types, and other integer types as an int32 when they fit and
+// an int64 otherwise. A json.Number is encoded as an integer when it has no
+// fraction and fits within an int64, and as a double otherwise. A time.Time is
+// encoded as a datetime and a []byte as generic binary data.
+//
+// Returns ErrNotObj if the element is not an object.
+func (g *Container) BSON() ([]byte, error) {
+	m, ok := g.Data().(map[string]interface{})
+	if !ok {
+		return nil, ErrNotObj
+	}
+	var e bsonEncoder
+	if err := e.encodeDocument(m); err != nil {
+		return nil, err
+	}
+	return e.b, nil
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	bsonDouble     byte = 0x01
+	bsonString     byte = 0x02
+	bsonDocument   byte = 0x03
+	bsonArray      byte = 0x04
+	bsonBinary     byte = 0x05
+	bsonUndefined  byte = 0x06
+	bsonObjectID   byte = 0x07
+	bsonBool       byte = 0x08
+	bsonDateTime   byte = 0x09
+	bsonNull       byte = 0x0A
+	bsonRegex      byte = 0x0B
+	bsonJavaScript byte = 0x0D
+	bsonSymbol     byte = 0x0E
+	bsonInt32      byte = 0x10
+	bsonTimestamp  byte = 0x11
+	bsonInt64      byte = 0x12
+	bsonDecimal128 byte = 0x13
+	bsonMinKey     byte = 0xFF
+	bsonMaxKey     byte = 0x7F
+)
+
+var (
+	errBSONShort    = errors.New("bson: unexpected end of input")
+	errBSONMaxDepth = errors.New("bson: exceeded maximum nesting depth")
+)
+
+const bsonMaxDepth = 10000
+
+type bsonDecoder struct {
+	b     []byte
+	pos   int
+	depth int
+}
+
+func (d *bsonDecoder) read(n int) ([]byte, error) {
+	if n < 0 || len(d.b)-d.pos < n {
+		return nil, errBSONShort
+	}
+	b := d.b[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *bsonDecoder) readInt32() (int32, error) {
+	b, err := d.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(binary.LittleEndian.Uint32(b)), nil
+}
+
+func (d *bsonDecoder) readUint64() (uint64, error) {
+	b, err := d.read(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+func (d *bsonDecoder) readCString() (string, error) {
+	i := bytes.IndexByte(d.b[d.pos:], 0)
+	if i < 0 {
+		return "", errBSONShort
+	}
+	s := string(d.b[d.pos : d.pos+i])
+	d.pos += i + 1
+	return s, nil
+}
+
+func (d *bsonDecoder) readString() (string, error) {
+	l, err := d.readInt32()
+	if err != nil {
+		return "", err
+	}
+	if l < 1 {
+		return "", fmt.Errorf("bson: invalid string length %v", l)
+	}
+	b, err := d.read(int(l))
+	if err != nil {
+		return "", err
+	}
+	if b[len(b)-1] != 0 {
+		return "", errors.New("bson: string is not null terminated")
+	}
+	return string(b[:len(b)-1]), nil
+}
+
+func (d *bsonDecoder) decodeDocument(isArray bool) (interface{}, error) {
+	d.depth++
+	defer func() {
+		d.depth--
+	}()
+	if d.depth > bsonMaxDepth {
+		return nil, errBSONMaxDepth
+	}
+
+	start := d.pos
+	l, err := d.readInt32()
+	if err != nil {
+		return nil, err
+	}
+	if l < 5 || int(l) > len(d.b)-start {
+		return nil, fmt.Errorf("bson: invalid document length %v", l)
+	}
+	end := start + int(l)
+
+	m := map[string]interface{}{}
+	var arr []interface{}
+	if isArray {
+		arr = []interface{}{}
+	}
+	for {
+		if d.pos >= end {
+			return nil, errors.New("bson: document is not null terminated")
+		}
+		t := d.b[d.pos]
+		d.pos++
+		if t == 0 {
+			break
+		}
+		key, err := d.readCString()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.decodeValue(t)
+		if err == errBSONMaxDepth {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bson: failed to decode field '%v': %w", key, err)
+		}
+		if isArray {
+			arr = append(arr, v)
+		} else {
+			m[key] = v
+		}
+	}
+	if d.pos != end {
+		return nil, errors.New("bson: document length does not match contents")
+	}
+	if isArray {
+		return arr, nil
+	}
+	return m, nil
+}
+
+func (d *bsonDecoder) decodeValue(t byte) (interface{}, error) {
+	switch t {
+	case bsonDouble:
+		u, err := d.readUint64()
+		return math.Float64frombits(u), err
+	case bsonString, bsonSymbol:
+		return d.readString()
+	case bsonJavaScript:
+		s, err := d.readString()
+		return BSONJavaScript(s), err
+	case bsonDocument:
+		return d.decodeDocument(false)
+	case bsonArray:
+		return d.decodeDocument(true)
+	case bsonBinary:
+		l, err := d.readInt32()
+		if err != nil {
+			return nil, err
+		}
+		subtype, err := d.read(1)
+		if err != nil {
+			return nil, err
+		}
+		data, err := d.read(int(l))
+		if err != nil {
+			return nil, err
+		}
+		return BSONBinary{Subtype: subtype[0], Data: append([]byte{}, data...)}, nil
+	case bsonUndefined, bsonNull:
+		return nil, nil
+	case bsonObjectID:
+		b, err := d.read(12)
+		if err != nil {
+			return nil, err
+		}
+		var o BSONObjectID
+		copy(o[:], b)
+		return o, nil
+	case bsonBool:
+		b, err := d.read(1)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] > 1 {
+			return nil, fmt.Errorf("bson: invalid boolean value %v", b[0])
+		}
+		return b[0] == 1, nil
+	case bsonDateTime:
+		u, err := d.readUint64()
+		if err != nil {
+			return nil, err
+		}
+		return bsonMillisToTime(int64(u)), nil
+	case bsonRegex:
+		pattern, err := d.readCString()
+		if err != nil {
+			return nil, err
+		}
+		options, err := d.readCString()
+		return BSONRegex{Pattern: pattern, Options: options}, err
+	case bsonInt32:
+		return d.readInt32()
+	case bsonTimestamp:
+		u, err := d.readUint64()
+		return BSONTimestamp{T: uint32(u >> 32), I: uint32(u)}, err
+	case bsonInt64:
+		u, err := d.readUint64()
+		return int64(u), err
+	case bsonDecimal128:
+		l, err := d.readUint64()
+		if err != nil {
+			return nil, err
+		}
+		h, err := d.readUint64()
+		return BSONDecimal128{H: h, L: l}, err
+	case bsonMinKey:
+		return BSONMinKey{}, nil
+	case bsonMaxKey:
+		return BSONMaxKey{}, nil
+	}
+	return nil, fmt.Errorf("unsupported element type 0x%02x", t)
+}
+
+func bsonMillisToTime(ms int64) time.Time {
+	return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond)).UTC()
+}
+
+func bsonTimeToMillis(t time.Time) int64 {
+	return t.Unix()*1000 + int64(t.Nanosecond())/int64(time.Millisecond)
+}
+
+//------------------------------------------------------------------------------
+
+type bsonEncoder struct {
+	b []byte
+}
+
+func (e *bsonEncoder) writeInt32(i int32) {
+	e.b = append(e.b, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(e.b[len(e.b)-4:], uint32(i))
+}
+
+func (e *bsonEncoder) writeUint64(u uint64) {
+	e.b = append(e.b, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint64(e.b[len(e.b)-8:], u)
+}
+
+func (e *bsonEncoder) writeCString(s string) error {
+	if strings.IndexByte(s, 0) >= 0 {
+		return fmt.Errorf("bson: key or pattern '%v' contains a null byte", s)
+	}
+	e.b = append(e.b, s...)
+	e.b = append(e.b, 0)
+	return nil
+}
+
+func (e *bsonEncoder) writeString(s string) {
+	e.writeInt32(int32(len(s) + 1))
+	e.b = append(e.b, s...)
+	e.b = append(e.b, 0)
+}
+
+func (e *bsonEncoder) encodeDocument(m map[string]interface{}) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	start := len(e.b)
+	e.writeInt32(0)
+	for _, k := range keys {
+		if err := e.encodeElement(k, m[k]); err != nil {
+			return err
+		}
+	}
+	e.b = append(e.b, 0)
+	binary.LittleEndian.PutUint32(e.b[start:], uint32(len(e.b)-start))
+	return nil
+}
+
+func (e *bsonEncoder) encodeArray(a []interface{}) error {
+	start := len(e.b)
+	e.writeInt32(0)
+	for i, v := range a {
+		if err := e.encodeElement(strconv.Itoa(i), v); err != nil {
+			return err
+		}
+	}
+	e.b = append(e.b, 0)
+	binary.LittleEndian.PutUint32(e.b[start:], uint32(len(e.b)-start))
+	return nil
+}
+
+func (e *bsonEncoder) encodeInt(key string, i int64) error {
+	if i >= math.MinInt32 && i <= math.MaxInt32 {
+		return e.encodeElement(key, int32(i))
+	}
+	return e.encodeElement(key, i)
+}
+
+// encodeElement writes a type byte, key and value.
+func (e *bsonEncoder) encodeElement(key string, v interface{}) error {
+	typePos := len(e.b)
+	e.b = append(e.b, 0)
+	if err := e.writeCString(key); err != nil {
+		return err
+	}
+
+	var t byte
+	switch val := v.(type) {
+	case nil:
+		t = bsonNull
+	case bool:
+		t = bsonBool
+		if val {
+			e.b = append(e.b, 1)
+		} else {
+			e.b = append(e.b, 0)
+		}
+	case string:
+		t = bsonString
+		e.writeString(val)
+	case float64:
+		t = bsonDouble
+		e.writeUint64(math.Float64bits(val))
+	case float32:
+		t = bsonDouble
+		e.writeUint64(math.Float64bits(float64(val)))
+	case int32:
+		t = bsonInt32
+		e.writeInt32(val)
+	case int64:
+		t = bsonInt64
+		e.writeUint64(uint64(val))
+	case int, int8, int16, uint8, uint16, uint32, uint, uint64, json.Number, *big.Int:
+		e.b = e.b[:typePos]
+		r, _ := toRat(val)
+		if r == nil || !r.IsInt() || !r.Num().IsInt64() {
+			if f, ok := val.(json.Number); ok {
+				n, err := f.Float64()
+				if err != nil {
+					return fmt.Errorf("bson: invalid number literal '%v'", f)
+				}
+				return e.encodeElement(key, n)
+			}
+			return fmt.Errorf("bson: integer value of field '%v' overflows int64", key)
+		}
+		return e.encodeInt(key, r.Num().Int64())
+	case time.Time:
+		t = bsonDateTime
+		e.writeUint64(uint64(bsonTimeToMillis(val)))
+	case []byte:
+		t = bsonBinary
+		e.writeInt32(int32(len(val)))
+		e.b = append(e.b, 0)
+		e.b = append(e.b, val...)
+	case BSONBinary:
+		t = bsonBinary
+		e.writeInt32(int32(len(val.Data)))
+		e.b = append(e.b, val.Subtype)
+		e.b = append(e.b, val.Data...)
+	case BSONObjectID:
+		t = bsonObjectID
+		e.b = append(e.b, val[:]...)
+	case BSONDecimal128:
+		t = bsonDecimal128
+		e.writeUint64(val.L)
+		e.writeUint64(val.H)
+	case BSONTimestamp:
+		t = bsonTimestamp
+		e.writeUint64(uint64(val.T)<<32 | uint64(val.I))
+	case BSONRegex:
+		t = bsonRegex
+		if err := e.writeCString(val.Pattern); err != nil {
+			return err
+		}
+		if err := e.writeCString(val.Options); err != nil {
+			return err
+		}
+	case BSONJavaScript:
+		t = bsonJavaScript
+		e.writeString(string(val))
+	case BSONMinKey:
+		t = bsonMinKey
+	case BSONMaxKey:
+		t = bsonMaxKey
+	case map[string]interface{}:
+		t = bsonDocument
+		if err := e.encodeDocument(val); err != nil {
+			return err
+		}
+	case []interface{}:
+		t = bsonArray
+		if err := e.encodeArray(val); err != nil {
+			return err
+		}
+	default:
+		e.b = e.b[:typePos]
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		return e.encodeElement(key, generic)
+	}
+	e.b[typePos] = t
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	decimal128Bias        = 6176
+	decimal128MaxExponent = 6111
+	decimal128MinExponent = -6176
+	decimal128MaxDigits   = 34
+)
+
+var decimal128MaxCoefficient = new(big.Int).Exp(big.NewInt(10), big.NewInt(decimal128MaxDigits), nil)
+
+// String returns the string representation of the decimal following the rules
+// of the BSON decimal128 specification.
+func (d BSONDecimal128) String() string {
+	sign := ""
+	if d.H>>63 == 1 {
+		sign = "-"
+	}
+	switch (d.H >> 58) & 0x1f {
+	case 0x1f:
+		return "NaN"
+	case 0x1e:
+		return sign + "Infinity"
+	}
+
+	var exp int
+	coefficient := new(big.Int)
+	if (d.H>>61)&3 == 3 {
+		// Coefficients of this form always exceed the maximum and are
+		// therefore non-canonical, which are interpreted as zero.
+		exp = int((d.H>>47)&0x3fff) - decimal128Bias
+	} else {
+		exp = int((d.H>>49)&0x3fff) - decimal128Bias
+		coefficient.SetUint64(d.H & (1<<49 - 1))
+		coefficient.Lsh(coefficient, 64)
+		coefficient.Or(coefficient, new(big.Int).SetUint64(d.L))
+		if coefficient.Cmp(decimal128MaxCoefficient) >= 0 {
+			coefficient.SetInt64(0)
+		}
+	}
+
+	digits := coefficient.String()
+	adjusted := exp + len(digits) - 1
+	if exp > 0 || adjusted < -6 {
+		s := digits[:1]
+		if len(digits) > 1 {
+			s += "." + digits[1:]
+		}
+		return fmt.Sprintf("%v%vE%+d", sign, s, adjusted)
+	}
+	if exp == 0 {
+		return sign + digits
+	}
+	if pointPos := len(digits) + exp; pointPos > 0 {
+		return sign + digits[:pointPos] + "." + digits[pointPos:]
+	}
+	return sign + "0." + strings.Repeat("0", -exp-len(digits)) + digits
+}
+
+// ParseBSONDecimal128 parses a decimal string, such as "1.05", "-1E+3",
+// "Infinity" or "NaN", into a BSONDecimal128. Returns an error if the value
+// cannot be represented exactly.
+func ParseBSONDecimal128(s string) (BSONDecimal128, error) {
+	orig := s
+	var neg bool
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	var signBit uint64
+	if neg {
+		signBit = 1 << 63
+	}
+	switch strings.ToLower(s) {
+	case "nan":
+		return BSONDecimal128{H: 0x7c00000000000000}, nil
+	case "inf", "infinity":
+		return BSONDecimal128{H: signBit | 0x7800000000000000}, nil
+	}
+
+	exp := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		if exp, err = strconv.Atoi(s[i+1:]); err != nil {
+			return BSONDecimal128{}, fmt.Errorf("invalid decimal128 string '%v'", orig)
+		}
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		exp -= len(s) - i - 1
+		s = s[:i] + s[i+1:]
+	}
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return BSONDecimal128{}, fmt.Errorf("invalid decimal128 string '%v'", orig)
+	}
+
+	coefficient, _ := new(big.Int).SetString(s, 10)
+	ten := big.NewInt(10)
+	mod := new(big.Int)
+	for coefficient.Cmp(decimal128MaxCoefficient) >= 0 || exp < decimal128MinExponent {
+		if q, r := new(big.Int).QuoRem(coefficient, ten, mod); r.Sign() == 0 {
+			coefficient = q
+			exp++
+		} else {
+			return BSONDecimal128{}, fmt.Errorf("decimal128 string '%v' cannot be represented exactly", orig)
+		}
+	}
+	for exp > decimal128MaxExponent {
+		coefficient.Mul(coefficient, ten)
+		exp--
+		if coefficient.Cmp(decimal128MaxCoefficient) >= 0 {
+			return BSONDecimal128{}, fmt.Errorf("decimal128 string '%v' overflows", orig)
+		}
+	}
+
+	low := new(big.Int).And(coefficient, new(big.Int).SetUint64(math.MaxUint64))
+	high := new(big.Int).Rsh(coefficient, 64)
+	return BSONDecimal128{
+		H: signBit | uint64(exp+decimal128Bias)<<49 | high.Uint64(),
+		L: low.Uint64(),
+	}, nil
+}
+
+//------------------------------------------------------------------------------
+
+// ExtJSONMode selects the format of MongoDB Extended JSON.
+type ExtJSONMode int
+
+// ExtJSONMode variants.
+const (
+	// ExtJSONRelaxed produces relaxed Extended JSON, where numbers and recent
+	// dates use their plain JSON representation.
+	ExtJSONRelaxed ExtJSONMode = iota
+
+	// ExtJSONCanonical produces canonical Extended JSON, which preserves type
+	// information for all values.
+	ExtJSONCanonical
+)
+
+// ExtendedJSON marshals an element to MongoDB Extended JSON (v2) in either
+// canonical or relaxed mode. Values are typed following the same rules as
+// BSON.
+func (g *Container) ExtendedJSON(mode ExtJSONMode) ([]byte, error) {
+	v, err := toExtJSON(g.Data(), mode == ExtJSONCanonical)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// ParseExtendedJSON parses MongoDB Extended JSON in either canonical or relaxed
+// mode into a *Container, where type wrapper objects such as {"$oid":"..."}
+// are converted into the same types produced by ParseBSON.
+//
+// Plain JSON numbers are parsed as int32 or int64 when they are integers that
+// fit within those types, and as float64 otherwise.
+func ParseExtendedJSON(sample []byte) (*Container, error) {
+	dec := json.NewDecoder(bytes.NewReader(sample))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, ErrInvalidInputText
+	}
+	v, err := fromExtJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Container{object: v}, nil
+}
+
+func formatExtJSONDouble(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	s := strconv.FormatFloat(f, 'G', -1, 64)
+	mantissa, exp := s, ""
+	if i := strings.IndexByte(s, 'E'); i >= 0 {
+		mantissa, exp = s[:i], s[i:]
+	}
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	return mantissa + exp
+}
+
+func toExtJSON(v interface{}, canonical bool) (interface{}, error) {
+	switch t := v.(type) {
+	case nil, bool, string:
+		return t, nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			var err error
+			if m[k], err = toExtJSON(e, canonical); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case []interface{}:
+		a := make([]interface{}, len(t))
+		for i, e := range t {
+			var err error
+			if a[i], err = toExtJSON(e, canonical); err != nil {
+				return nil, err
+			}
+		}
+		return a, nil
+	case float64:
+		if canonical || math.IsInf(t, 0) || math.IsNaN(t) {
+			return map[string]interface{}{"$numberDouble": formatExtJSONDouble(t)}, nil
+		}
+		return t, nil
+	case float32:
+		return toExtJSON(float64(t), canonical)
+	case int32:
+		if canonical {
+			return map[string]interface{}{"$numberInt": strconv.FormatInt(int64(t), 10)}, nil
+		}
+		return t, nil
+	case int64:
+		if canonical {
+			return map[string]interface{}{"$numberLong": strconv.FormatInt(t, 10)}, nil
+		}
+		return t, nil
+	case int, int8, int16, uint8, uint16, uint32, uint, uint64, json.Number, *big.Int:
+		r, _ := toRat(t)
+		if r == nil || !r.IsInt() || !r.Num().IsInt64() {
+			if n, ok := t.(json.Number); ok {
+				f, err := n.Float64()
+				if err != nil {
+					return nil, fmt.Errorf("invalid number literal '%v'", n)
+				}
+				return toExtJSON(f, canonical)
+			}
+			return nil, errors.New("integer value overflows int64")
+		}
+		if i := r.Num().Int64(); i < math.MinInt32 || i > math.MaxInt32 {
+			return toExtJSON(i, canonical)
+		}
+		return toExtJSON(int32(r.Num().Int64()), canonical)
+	case time.Time:
+		ms := bsonTimeToMillis(t)
+		if !canonical && t.Year() >= 1970 && t.Year() <= 9999 {
+			return map[string]interface{}{"$date": t.UTC().Format("2006-01-02T15:04:05.000Z07:00")}, nil
+		}
+		return map[string]interface{}{
+			"$date": map[string]interface{}{"$numberLong": strconv.FormatInt(ms, 10)},
+		}, nil
+	case []byte:
+		return toExtJSON(BSONBinary{Data: t}, canonical)
+	case BSONBinary:
+		return map[string]interface{}{"$binary": map[string]interface{}{
+			"base64":  base64.StdEncoding.EncodeToString(t.Data),
+			"subType": fmt.Sprintf("%02x", t.Subtype),
+		}}, nil
+	case BSONObjectID:
+		return map[string]interface{}{"$oid": t.String()}, nil
+	case BSONDecimal128:
+		return map[string]interface{}{"$numberDecimal": t.String()}, nil
+	case BSONTimestamp:
+		return map[string]interface{}{"$timestamp": map[string]interface{}{"t": t.T, "i": t.I}}, nil
+	case BSONRegex:
+		return map[string]interface{}{"$regularExpression": map[string]interface{}{
+			"pattern": t.Pattern,
+			"options": t.Options,
+		}}, nil
+	case BSONJavaScript:
+		return map[string]interface{}{"$code": string(t)}, nil
+	case BSONMinKey:
+		return map[string]interface{}{"$minKey": 1}, nil
+	case BSONMaxKey:
+		return map[string]interface{}{"$maxKey": 1}, nil
+	}
+	generic, err := toGeneric(v)
+	if err != nil {
+		return nil, err
+	}
+	return toExtJSON(generic, canonical)
+}
+
+func fromExtJSON(v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(string(t), 10, 64); err == nil {
+			if i >= math.MinInt32 && i <= math.MaxInt32 {
+				return int32(i), nil
+			}
+			return i, nil
+		}
+		return t.Float64()
+	case []interface{}:
+		for i, e := range t {
+			var err error
+			if t[i], err = fromExtJSON(e); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	case map[string]interface{}:
+		if wrapped, isWrapper, err := fromExtJSONWrapper(t); isWrapper {
+			return wrapped, err
+		}
+		for k, e := range t {
+			var err error
+			if t[k], err = fromExtJSON(e); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	}
+	return v, nil
+}
+
+// fromExtJSONWrapper converts a type wrapper object into the type it
+// represents. Returns false if the object is not a type wrapper.
+func fromExtJSONWrapper(m map[string]interface{}) (interface{}, bool, error) {
+	var key string
+	for k := range m {
+		if !strings.HasPrefix(k, "$") {
+			return nil, false, nil
+		}
+		key = k
+	}
+	if len(m) == 2 {
+		// The legacy form of binary data is the only wrapper with two keys.
+		_, hasBinary := m["$binary"]
+		_, hasType := m["$type"]
+		if !hasBinary || !hasType {
+			return nil, false, nil
+		}
+		key = "$binary"
+	} else if len(m) != 1 {
+		return nil, false, nil
+	}
+
+	invalid := func() (interface{}, bool, error) {
+		return nil, true, fmt.Errorf("invalid extended JSON value for '%v'", key)
+	}
+	str, isStr := m[key].(string)
+	obj, isObj := m[key].(map[string]interface{})
+
+	switch key {
+	case "$oid":
+		b, err := hex.DecodeString(str)
+		if !isStr || err != nil || len(b) != 12 {
+			return invalid()
+		}
+		var o BSONObjectID
+		copy(o[:], b)
+		return o, true, nil
+	case "$numberInt":
+		i, err := strconv.ParseInt(str, 10, 32)
+		if !isStr || err != nil {
+			return invalid()
+		}
+		return int32(i), true, nil
+	case "$numberLong":
+		i, err := strconv.ParseInt(str, 10, 64)
+		if !isStr || err != nil {
+			return invalid()
+		}
+		return i, true, nil
+	case "$numberDouble":
+		var f float64
+		var err error
+		switch str {
+		case "Infinity":
+			f = math.Inf(1)
+		case "-Infinity":
+			f = math.Inf(-1)
+		case "NaN":
+			f = math.NaN()
+		default:
+			f, err = strconv.ParseFloat(str, 64)
+		}
+		if !isStr || err != nil {
+			return invalid()
+		}
+		return f, true, nil
+	case "$numberDecimal":
+		d, err := ParseBSONDecimal128(str)
+		if !isStr || err != nil {
+			return invalid()
+		}
+		return d, true, nil
+	case "$binary":
+		var b64, subtype string
+		if isObj {
+			b64, _ = obj["base64"].(string)
+			subtype, _ = obj["subType"].(string)
+		} else {
+			b64 = str
+			subtype, _ = m["$type"].(string)
+		}
+		data, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			return invalid()
+		}
+		st, err := strconv.ParseUint(subtype, 16, 8)
+		if err != nil {
+			return invalid()
+		}
+		return BSONBinary{Subtype: byte(st), Data: data}, true, nil
+	case "$date":
+		switch {
+		case isStr:
+			t, err := time.Parse(time.RFC3339Nano, str)
+			if err != nil {
+				return invalid()
+			}
+			return t.UTC(), true, nil
+		case isObj:
+			ms, err := strconv.ParseInt(fmt.Sprintf("%v", obj["$numberLong"]), 10, 64)
+			if err != nil || len(obj) != 1 {
+				return invalid()
+			}
+			return bsonMillisToTime(ms), true, nil
+		}
+		if n, ok := m[key].(json.Number); ok {
+			ms, err := n.Int64()
+			if err != nil {
+				return invalid()
+			}
+			return bsonMillisToTime(ms), true, nil
+		}
+		return invalid()
+	case "$timestamp":
+		tVal, tErr := strconv.ParseUint(fmt.Sprintf("%v", obj["t"]), 10, 32)
+		iVal, iErr := strconv.ParseUint(fmt.Sprintf("%v", obj["i"]), 10, 32)
+		if !isObj || tErr != nil || iErr != nil {
+			return invalid()
+		}
+		return BSONTimestamp{T: uint32(tVal), I: uint32(iVal)}, true, nil
+	case "$regularExpression":
+		pattern, pOk := obj["pattern"].(string)
+		options, oOk := obj["options"].(string)
+		if !isObj || !pOk || !oOk {
+			return invalid()
+		}
+		return BSONRegex{Pattern: pattern, Options: options}, true, nil
+	case "$code":
+		if !isStr {
+			return invalid()
+		}
+		return BSONJavaScript(str), true, nil
+	case "$symbol":
+		if !isStr {
+			return invalid()
+		}
+		return str, true, nil
+	case "$minKey":
+		return BSONMinKey{}, true, nil
+	case "$maxKey":
+		return BSONMaxKey{}, true, nil
+	case "$undefined":
+		return nil, true, nil
+	}
+	return nil, false, nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,328 @@
+package gabs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBSONDecode(t *testing.T) {
+	type testCase struct {
+		input  string
+		output string
+	}
+	tests := []testCase{
+		{input: "0500000000", output: `{}`},
+		{input: "160000000268656c6c6f0006000000776f726c640000", output: `{"hello":"world"}`},
+		{input: "0c0000001061000500000000", output: `{"a":5}`},
+		{input: "100000000161000000000000000e4000", output: `{"a":3.75}`},
+		{input: "10000000126100010000000100000000", output: `{"a":4294967297}`},
+		{input: "090000000861000100", output: `{"a":true}`},
+		{input: "080000000a610000", output: `{"a":null}`},
+		{input: "0800000006610000", output: `{"a":null}`},
+		{input: "1b0000000461001300000010300001000000103100020000000000", output: `{"a":[1,2]}`},
+		{input: "160000000361000e0000000262000200000063000000", output: `{"a":{"b":"c"}}`},
+	}
+
+	for i, test := range tests {
+		b, err := hex.DecodeString(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := ParseBSON(b)
+		if err != nil {
+			t.Errorf("[%d] Failed to parse: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, c.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestBSONEncode(t *testing.T) {
+	type testCase struct {
+		input  interface{}
+		output string
+	}
+	tests := []testCase{
+		{input: map[string]interface{}{}, output: "0500000000"},
+		{input: map[string]interface{}{"hello": "world"}, output: "160000000268656c6c6f0006000000776f726c640000"},
+		{input: map[string]interface{}{"a": 5}, output: "0c0000001061000500000000"},
+		{input: map[string]interface{}{"a": int64(5)}, output: "10000000126100050000000000000000"},
+		{input: map[string]interface{}{"a": 3.75}, output: "100000000161000000000000000e4000"},
+		{input: map[string]interface{}{"a": true}, output: "090000000861000100"},
+		{input: map[string]interface{}{"a": nil}, output: "080000000a610000"},
+		{input: map[string]interface{}{"a": []interface{}{1, 2}}, output: "1b0000000461001300000010300001000000103100020000000000"},
+		{input: map[string]interface{}{"b": "c", "a": 1}, output: "150000001061000100000002620002000000630000"},
+		{input: map[string]interface{}{"a": []byte{1, 2}}, output: "0f0000000561000200000000010200"},
+	}
+
+	for i, test := range tests {
+		b, err := Wrap(test.input).BSON()
+		if err != nil {
+			t.Errorf("[%d] Failed to encode: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, hex.EncodeToString(b); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+
+	if _, err := Wrap([]interface{}{1}).BSON(); err != ErrNotObj {
+		t.Errorf("Expected ErrNotObj, received: %v", err)
+	}
+	if _, err := Wrap(map[string]interface{}{"a\x00b": 1}).BSON(); err == nil {
+		t.Error("Expected error from key containing null byte")
+	}
+}
+
+func TestBSONRoundTrip(t *testing.T) {
+	oid := BSONObjectID{0x5f, 0x1d, 0x7f, 0x2a, 0x1c, 0x9d, 0x44, 0x00, 0x00, 0x00, 0x00, 0x01}
+	dec, err := ParseBSONDecimal128("1.05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	when := time.Date(2020, 7, 26, 13, 4, 5, 123000000, time.UTC)
+
+	input := map[string]interface{}{
+		"oid":   oid,
+		"i32":   int32(-7),
+		"i64":   int64(math.MaxInt64),
+		"dbl":   1.5,
+		"date":  when,
+		"dec":   dec,
+		"bin":   BSONBinary{Subtype: 4, Data: []byte{1, 2, 3}},
+		"ts":    BSONTimestamp{T: 10, I: 2},
+		"regex": BSONRegex{Pattern: "^a", Options: "i"},
+		"js":    BSONJavaScript("x = 1"),
+		"min":   BSONMinKey{},
+		"max":   BSONMaxKey{},
+		"doc":   map[string]interface{}{"arr": []interface{}{"a", false}},
+	}
+
+	b, err := Wrap(input).BSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := ParseBSON(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(Wrap(input), c) {
+		t.Errorf("Wrong result: %v != %v", c.Data(), input)
+	}
+	if act, ok := c.Path("i32").Data().(int32); !ok || act != -7 {
+		t.Errorf("Wrong int32: %#v", c.Path("i32").Data())
+	}
+	if act, ok := c.Path("date").Data().(time.Time); !ok || !act.Equal(when) {
+		t.Errorf("Wrong date: %v != %v", c.Path("date").Data(), when)
+	}
+
+	reencoded, err := c.BSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, reencoded) {
+		t.Errorf("Wrong result: %x != %x", reencoded, b)
+	}
+}
+
+func TestBSONDecodeErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"05000000",
+		"0600000000",
+		"0500000001",
+		"0500000000ff",
+		"0c0000001061000500000000ff",
+		"0c0000002061000500000000",
+		"0d000000026100ffffffff0000",
+		"090000000861000200",
+	}
+
+	for i, test := range tests {
+		b, err := hex.DecodeString(test)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = ParseBSON(b); err == nil {
+			t.Errorf("[%d] Expected error", i)
+		}
+	}
+	var deep []byte
+	for i := bsonMaxDepth; i > 0; i-- {
+		l := 5 + 8*i
+		deep = append(deep, byte(l), byte(l>>8), byte(l>>16), byte(l>>24), 0x03, 'a', 0)
+	}
+	deep = append(deep, 5, 0, 0, 0, 0)
+	deep = append(deep, make([]byte, bsonMaxDepth)...)
+	if _, err := ParseBSON(deep); err != errBSONMaxDepth {
+		t.Errorf("Expected depth error, received: %v", err)
+	}
+}
+
+func TestBSONDecimal128(t *testing.T) {
+	type testCase struct {
+		input  string
+		h, l   uint64
+		output string
+	}
+	tests := []testCase{
+		{input: "0", h: 0x3040000000000000, l: 0, output: "0"},
+		{input: "-0", h: 0xb040000000000000, l: 0, output: "-0"},
+		{input: "1", h: 0x3040000000000000, l: 1, output: "1"},
+		{input: "-1", h: 0xb040000000000000, l: 1, output: "-1"},
+		{input: "0.001234", h: 0x3034000000000000, l: 1234, output: "0.001234"},
+		{input: "1.05", h: 0x303c000000000000, l: 105, output: "1.05"},
+		{input: "1E+3", h: 0x3046000000000000, l: 1, output: "1E+3"},
+		{input: "1E-7", h: 0x3032000000000000, l: 1, output: "1E-7"},
+		{input: "12345678901234567", h: 0x3040000000000000, l: 12345678901234567, output: "12345678901234567"},
+		{input: "9.999999999999999999999999999999999E+6144", h: 0x5fffed09bead87c0, l: 0x378d8e63ffffffff, output: "9.999999999999999999999999999999999E+6144"},
+		{input: "Infinity", h: 0x7800000000000000, l: 0, output: "Infinity"},
+		{input: "-Infinity", h: 0xf800000000000000, l: 0, output: "-Infinity"},
+		{input: "NaN", h: 0x7c00000000000000, l: 0, output: "NaN"},
+	}
+
+	for i, test := range tests {
+		d, err := ParseBSONDecimal128(test.input)
+		if err != nil {
+			t.Errorf("[%d] Failed to parse: %v", i, err)
+			continue
+		}
+		if d.H != test.h || d.L != test.l {
+			t.Errorf("[%d] Wrong bits: %x %x != %x %x", i, d.H, d.L, test.h, test.l)
+		}
+		if exp, act := test.output, d.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+
+	for i, test := range []string{"", "abc", "1.2.3", "1E", "1E+99999", "12345678901234567890123456789012345"} {
+		if _, err := ParseBSONDecimal128(test); err == nil {
+			t.Errorf("[%d] Expected error from '%v'", i, test)
+		}
+	}
+}
+
+func TestExtendedJSON(t *testing.T) {
+	oid := BSONObjectID{0x5f, 0x1d, 0x7f, 0x2a, 0x1c, 0x9d, 0x44, 0x00, 0x00, 0x00, 0x00, 0x01}
+	when := time.Date(2020, 7, 26, 13, 4, 5, 123000000, time.UTC)
+	c := Wrap(map[string]interface{}{
+		"oid":  oid,
+		"i32":  int32(7),
+		"i64":  int64(1) << 40,
+		"dbl":  1.0,
+		"date": when,
+		"bin":  []byte{1, 2, 3},
+	})
+
+	type testCase struct {
+		mode   ExtJSONMode
+		output string
+	}
+	tests := []testCase{
+		{
+			mode:   ExtJSONCanonical,
+			output: `{"bin":{"$binary":{"base64":"AQID","subType":"00"}},"date":{"$date":{"$numberLong":"1595768645123"}},"dbl":{"$numberDouble":"1.0"},"i32":{"$numberInt":"7"},"i64":{"$numberLong":"1099511627776"},"oid":{"$oid":"5f1d7f2a1c9d440000000001"}}`,
+		},
+		{
+			mode:   ExtJSONRelaxed,
+			output: `{"bin":{"$binary":{"base64":"AQID","subType":"00"}},"date":{"$date":"2020-07-26T13:04:05.123Z"},"dbl":1,"i32":7,"i64":1099511627776,"oid":{"$oid":"5f1d7f2a1c9d440000000001"}}`,
+		},
+	}
+
+	for i, test := range tests {
+		b, err := c.ExtendedJSON(test.mode)
+		if err != nil {
+			t.Errorf("[%d] Failed to encode: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, string(b); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+
+		parsed, err := ParseExtendedJSON(b)
+		if err != nil {
+			t.Errorf("[%d] Failed to parse: %v", i, err)
+			continue
+		}
+		if act, ok := parsed.Path("oid").Data().(BSONObjectID); !ok || act != oid {
+			t.Errorf("[%d] Wrong oid: %v", i, parsed.Path("oid").Data())
+		}
+		if act, ok := parsed.Path("date").Data().(time.Time); !ok || !act.Equal(when) {
+			t.Errorf("[%d] Wrong date: %v", i, parsed.Path("date").Data())
+		}
+		if act, ok := parsed.Path("i64").Data().(int64); !ok || act != 1<<40 {
+			t.Errorf("[%d] Wrong int64: %#v", i, parsed.Path("i64").Data())
+		}
+		if act, ok := parsed.Path("i32").Data().(int32); !ok || act != 7 {
+			t.Errorf("[%d] Wrong int32: %#v", i, parsed.Path("i32").Data())
+		}
+	}
+}
+
+func TestParseExtendedJSON(t *testing.T) {
+	c, err := ParseExtendedJSON([]byte(`{
+		"a": {"$numberDouble": "-Infinity"},
+		"b": {"$numberDecimal": "1.05"},
+		"c": {"$timestamp": {"t": 10, "i": 2}},
+		"d": {"$regularExpression": {"pattern": "^a", "options": "i"}},
+		"e": {"$binary": "AQID", "$type": "04"},
+		"f": {"$minKey": 1},
+		"g": 2.5,
+		"h": {"$date": {"$numberLong": "-1000"}},
+		"i": {"$notAType": 1, "other": 2},
+		"j": {"$type": "string", "$exists": true},
+		"k": {"$gt": 1, "$lt": 5}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, ok := c.Path("a").Data().(float64); !ok || !math.IsInf(act, -1) {
+		t.Errorf("Wrong double: %v", c.Path("a").Data())
+	}
+	if exp, act := "1.05", c.Path("b").Data().(BSONDecimal128).String(); exp != act {
+		t.Errorf("Wrong decimal: %v != %v", act, exp)
+	}
+	if exp, act := (BSONTimestamp{T: 10, I: 2}), c.Path("c").Data(); exp != act {
+		t.Errorf("Wrong timestamp: %v != %v", act, exp)
+	}
+	if exp, act := (BSONRegex{Pattern: "^a", Options: "i"}), c.Path("d").Data(); exp != act {
+		t.Errorf("Wrong regex: %v != %v", act, exp)
+	}
+	if act, ok := c.Path("e").Data().(BSONBinary); !ok || act.Subtype != 4 || !bytes.Equal(act.Data, []byte{1, 2, 3}) {
+		t.Errorf("Wrong binary: %v", c.Path("e").Data())
+	}
+	if _, ok := c.Path("f").Data().(BSONMinKey); !ok {
+		t.Errorf("Wrong min key: %v", c.Path("f").Data())
+	}
+	if exp, act := 2.5, c.Path("g").Data(); exp != act {
+		t.Errorf("Wrong number: %v != %v", act, exp)
+	}
+	if exp, act := time.Unix(-1, 0).UTC(), c.Path("h").Data().(time.Time); !exp.Equal(act) {
+		t.Errorf("Wrong date: %v != %v", act, exp)
+	}
+	if !c.Exists("i", "other") {
+		t.Error("Expected plain object to be preserved")
+	}
+	if exp, act := `{"$exists":true,"$type":"string"}`, c.Path("j").String(); exp != act {
+		t.Errorf("Wrong query operators: %v != %v", act, exp)
+	}
+	if exp, act := `{"$gt":1,"$lt":5}`, c.Path("k").String(); exp != act {
+		t.Errorf("Wrong query operators: %v != %v", act, exp)
+	}
+
+	for i, test := range []string{
+		`{"$oid": "zz"}`,
+		`{"$numberInt": "4294967296"}`,
+		`{"$date": true}`,
+		`{} {}`,
+	} {
+		if _, err := ParseExtendedJSON([]byte(test)); err == nil {
+			t.Errorf("[%d] Expected error from '%v'", i, test)
+		}
+	}
+}
@@ -0,0 +1,583 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+//------------------------------------------------------------------------------
+
+// CBORTag represents a tagged CBOR data item with a tag number that is not
+// otherwise understood.
+type CBORTag struct {
+	Number  uint64
+	Content interface{}
+}
+
+// CBORSimple represents a CBOR simple value other than false, true, null or
+// undefined.
+type CBORSimple uint8
+
+// ParseCBOR decodes a CBOR (RFC 8949) encoded data item into a *Container. Maps
+// and arrays are decoded into map[string]interface{} and []interface{} just as
+// ParseJSON would produce, and map keys that are not strings are converted into
+// their JSON representation.
+//
+// Integers are decoded as float64 when they can be represented exactly,
+// otherwise as json.Number, and byte strings are decoded as []byte. Standard
+// date/time (0) and epoch (1) tags are decoded as time.Time, bignum tags (2 and
+// 3) as *big.Int and all other tags as CBORTag.
+func ParseCBOR(sample []byte) (*Container, error) {
+	d := cborDecoder{b: sample}
+	v, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	if v == cborBreak {
+		return nil, errors.New("cbor: unexpected break")
+	}
+	if d.pos != len(d.b) {
+		return nil, fmt.Errorf("cbor: unexpected trailing data at offset %v", d.pos)
+	}
+	return &Container{object: v}, nil
+}
+
+// CBOR encodes an element as CBOR following the core deterministic encoding
+// requirements of RFC 8949 section 4.2.1: integers, lengths and floats use
+// their shortest form, lengths are always definite and map keys are sorted by
+// the bytewise order of their encodings.
+//
+// Float values that are whole numbers are encoded as integers. A time.Time is
+// encoded as an epoch tag and a *big.Int that does not fit within a CBOR
+// integer as a bignum tag. Values of types that CBOR has no representation for
+// are encoded via their JSON representation.
+func (g *Container) CBOR() ([]byte, error) {
+	var e cborEncoder
+	if err := e.encode(g.Data()); err != nil {
+		return nil, err
+	}
+	return e.b, nil
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	cborMajorUint byte = iota
+	cborMajorNegInt
+	cborMajorBytes
+	cborMajorText
+	cborMajorArray
+	cborMajorMap
+	cborMajorTag
+	cborMajorSimple
+)
+
+type cborBreakType struct{}
+
+var (
+	cborBreak       = cborBreakType{}
+	errCBORShort    = errors.New("cbor: unexpected end of input")
+	errCBORMaxDepth = errors.New("cbor: exceeded maximum nesting depth")
+)
+
+const cborMaxDepth = 10000
+
+type cborDecoder struct {
+	b     []byte
+	pos   int
+	depth int
+}
+
+func (d *cborDecoder) read(n uint64) ([]byte, error) {
+	if n > uint64(len(d.b)-d.pos) {
+		return nil, errCBORShort
+	}
+	b := d.b[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// readHead reads the initial byte and argument of a data item. The returned
+// bool is true if the item has an indefinite length.
+func (d *cborDecoder) readHead() (major byte, info byte, arg uint64, indefinite bool, err error) {
+	var b []byte
+	if b, err = d.read(1); err != nil {
+		return
+	}
+	major, info = b[0]>>5, b[0]&0x1f
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info <= 27:
+		n := uint64(1) << (info - 24)
+		if b, err = d.read(n); err != nil {
+			return
+		}
+		for _, c := range b {
+			arg = arg<<8 | uint64(c)
+		}
+	case info == 31:
+		indefinite = true
+	default:
+		err = fmt.Errorf("cbor: invalid additional information %v at offset %v", info, d.pos-1)
+	}
+	return
+}
+
+func (d *cborDecoder) decode() (interface{}, error) {
+	d.depth++
+	defer func() {
+		d.depth--
+	}()
+	if d.depth > cborMaxDepth {
+		return nil, errCBORMaxDepth
+	}
+
+	major, info, arg, indefinite, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+	if indefinite && (major == cborMajorUint || major == cborMajorNegInt || major == cborMajorTag) {
+		return nil, fmt.Errorf("cbor: invalid indefinite length for major type %v", major)
+	}
+
+	switch major {
+	case cborMajorUint:
+		return exactUint(arg), nil
+	case cborMajorNegInt:
+		if arg < 1<<63 {
+			return exactInt(-1 - int64(arg)), nil
+		}
+		n := new(big.Int).SetUint64(arg)
+		return json.Number(n.Neg(n).Sub(n, big.NewInt(1)).String()), nil
+	case cborMajorBytes, cborMajorText:
+		b, err := d.decodeString(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborMajorBytes {
+			return b, nil
+		}
+		if !utf8.Valid(b) {
+			return nil, errors.New("cbor: invalid UTF-8 in text string")
+		}
+		return string(b), nil
+	case cborMajorArray:
+		return d.decodeArray(arg, indefinite)
+	case cborMajorMap:
+		return d.decodeMap(arg, indefinite)
+	case cborMajorTag:
+		return d.decodeTag(arg)
+	}
+
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return halfToFloat64(uint16(arg)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(arg))), nil
+	case 27:
+		return math.Float64frombits(arg), nil
+	case 31:
+		return cborBreak, nil
+	}
+	if info == 24 && arg < 32 {
+		return nil, errors.New("cbor: invalid simple value encoding")
+	}
+	return CBORSimple(arg), nil
+}
+
+func (d *cborDecoder) decodeString(major byte, l uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		b, err := d.read(l)
+		return append([]byte(nil), b...), err
+	}
+	var buf []byte
+	for {
+		chunkMajor, info, arg, chunkIndefinite, err := d.readHead()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor == cborMajorSimple && info == 31 {
+			return append([]byte{}, buf...), nil
+		}
+		if chunkMajor != major || chunkIndefinite {
+			return nil, errors.New("cbor: invalid chunk within indefinite length string")
+		}
+		chunk, err := d.read(arg)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, chunk...)
+	}
+}
+
+func (d *cborDecoder) decodeArray(l uint64, indefinite bool) (interface{}, error) {
+	if !indefinite && l > uint64(len(d.b)-d.pos) {
+		return nil, errCBORShort
+	}
+	arr := make([]interface{}, 0, int(l))
+	for i := uint64(0); indefinite || i < l; i++ {
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		if v == cborBreak {
+			if !indefinite {
+				return nil, errors.New("cbor: unexpected break")
+			}
+			break
+		}
+		arr = append(arr, v)
+	}
+	return arr, nil
+}
+
+func (d *cborDecoder) decodeMap(l uint64, indefinite bool) (interface{}, error) {
+	if !indefinite && l > uint64(len(d.b)-d.pos) {
+		return nil, errCBORShort
+	}
+	m := make(map[string]interface{}, int(l))
+	for i := uint64(0); indefinite || i < l; i++ {
+		k, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		if k == cborBreak {
+			if !indefinite {
+				return nil, errors.New("cbor: unexpected break")
+			}
+			break
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		if v == cborBreak {
+			return nil, errors.New("cbor: unexpected break")
+		}
+		m[stringifyKey(k)] = v
+	}
+	return m, nil
+}
+
+func (d *cborDecoder) decodeTag(number uint64) (interface{}, error) {
+	content, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	if content == cborBreak {
+		return nil, errors.New("cbor: unexpected break")
+	}
+
+	switch number {
+	case 0:
+		s, ok := content.(string)
+		if !ok {
+			return nil, errors.New("cbor: date/time tag content must be a text string")
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("cbor: invalid date/time: %v", err)
+		}
+		return t, nil
+	case 1:
+		switch t := content.(type) {
+		case float64:
+			if math.IsInf(t, 0) || math.IsNaN(t) {
+				return nil, errors.New("cbor: invalid epoch time")
+			}
+			sec, frac := math.Modf(t)
+			return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+		case json.Number:
+			sec, err := t.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("cbor: invalid epoch time: %v", err)
+			}
+			return time.Unix(sec, 0).UTC(), nil
+		}
+		return nil, errors.New("cbor: epoch tag content must be a number")
+	case 2, 3:
+		b, ok := content.([]byte)
+		if !ok {
+			return nil, errors.New("cbor: bignum tag content must be a byte string")
+		}
+		n := new(big.Int).SetBytes(b)
+		if number == 3 {
+			n.Neg(n).Sub(n, big.NewInt(1))
+		}
+		return n, nil
+	case 55799:
+		// Self-described CBOR carries no meaning beyond marking the data.
+		return content, nil
+	}
+	return CBORTag{Number: number, Content: content}, nil
+}
+
+//------------------------------------------------------------------------------
+
+func halfToFloat64(h uint16) float64 {
+	exp, mant := int(h>>10)&0x1f, float64(h&0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
+
+// float64ToHalf returns the half precision representation of a float if it
+// can be represented exactly.
+func float64ToHalf(f float64) (uint16, bool) {
+	if math.IsNaN(f) {
+		return 0x7e00, true
+	}
+	var sign uint16
+	if math.Signbit(f) {
+		sign, f = 0x8000, -f
+	}
+	if math.IsInf(f, 0) {
+		return sign | 0x7c00, true
+	}
+	if f == 0 {
+		return sign, true
+	}
+	frac, exp := math.Frexp(f)
+	if e := exp + 14; e >= 1 && e <= 30 {
+		if m := (2*frac - 1) * 1024; m == math.Trunc(m) {
+			return sign | uint16(e)<<10 | uint16(m), true
+		}
+		return 0, false
+	}
+	if m := math.Ldexp(f, 24); m < 1024 && m == math.Trunc(m) {
+		return sign | uint16(m), true
+	}
+	return 0, false
+}
+
+//------------------------------------------------------------------------------
+
+type cborEncoder struct {
+	b []byte
+}
+
+func (e *cborEncoder) writeHead(major byte, arg uint64) {
+	major <<= 5
+	switch {
+	case arg < 24:
+		e.b = append(e.b, major|byte(arg))
+	case arg <= math.MaxUint8:
+		e.b = append(e.b, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		e.b = append(e.b, major|25, 0, 0)
+		binary.BigEndian.PutUint16(e.b[len(e.b)-2:], uint16(arg))
+	case arg <= math.MaxUint32:
+		e.b = append(e.b, major|26, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(e.b[len(e.b)-4:], uint32(arg))
+	default:
+		e.b = append(e.b, major|27, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(e.b[len(e.b)-8:], arg)
+	}
+}
+
+func (e *cborEncoder) encodeInt(i int64) {
+	if i < 0 {
+		e.writeHead(cborMajorNegInt, uint64(-(i + 1)))
+	} else {
+		e.writeHead(cborMajorUint, uint64(i))
+	}
+}
+
+func (e *cborEncoder) encodeFloat(f float64) {
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 && !(f == 0 && math.Signbit(f)) {
+		e.encodeInt(int64(f))
+		return
+	}
+	if h, ok := float64ToHalf(f); ok {
+		e.b = append(e.b, cborMajorSimple<<5|25, byte(h>>8), byte(h))
+		return
+	}
+	if f32 := float32(f); float64(f32) == f {
+		e.b = append(e.b, cborMajorSimple<<5|26, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(e.b[len(e.b)-4:], math.Float32bits(f32))
+		return
+	}
+	e.b = append(e.b, cborMajorSimple<<5|27, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(e.b[len(e.b)-8:], math.Float64bits(f))
+}
+
+func (e *cborEncoder) encodeBigInt(n *big.Int) {
+	if n.Sign() >= 0 {
+		if n.IsUint64() {
+			e.writeHead(cborMajorUint, n.Uint64())
+			return
+		}
+		e.writeHead(cborMajorTag, 2)
+		b := n.Bytes()
+		e.writeHead(cborMajorBytes, uint64(len(b)))
+		e.b = append(e.b, b...)
+		return
+	}
+	// Negative values are encoded as -1 - n.
+	abs := new(big.Int).Neg(n)
+	abs.Sub(abs, big.NewInt(1))
+	if abs.IsUint64() {
+		e.writeHead(cborMajorNegInt, abs.Uint64())
+		return
+	}
+	e.writeHead(cborMajorTag, 3)
+	b := abs.Bytes()
+	e.writeHead(cborMajorBytes, uint64(len(b)))
+	e.b = append(e.b, b...)
+}
+
+func (e *cborEncoder) encode(v interface{}) error {
+	switch t := v.(type) {
+	case nil:
+		e.b = append(e.b, cborMajorSimple<<5|22)
+	case bool:
+		if t {
+			e.b = append(e.b, cborMajorSimple<<5|21)
+		} else {
+			e.b = append(e.b, cborMajorSimple<<5|20)
+		}
+	case string:
+		e.writeHead(cborMajorText, uint64(len(t)))
+		e.b = append(e.b, t...)
+	case []byte:
+		e.writeHead(cborMajorBytes, uint64(len(t)))
+		e.b = append(e.b, t...)
+	case float64:
+		e.encodeFloat(t)
+	case float32:
+		e.encodeFloat(float64(t))
+	case int:
+		e.encodeInt(int64(t))
+	case int8:
+		e.encodeInt(int64(t))
+	case int16:
+		e.encodeInt(int64(t))
+	case int32:
+		e.encodeInt(int64(t))
+	case int64:
+		e.encodeInt(t)
+	case uint:
+		e.writeHead(cborMajorUint, uint64(t))
+	case uint8:
+		e.writeHead(cborMajorUint, uint64(t))
+	case uint16:
+		e.writeHead(cborMajorUint, uint64(t))
+	case uint32:
+		e.writeHead(cborMajorUint, uint64(t))
+	case uint64:
+		e.writeHead(cborMajorUint, t)
+	case *big.Int:
+		e.encodeBigInt(t)
+	case json.Number:
+		if n, ok := new(big.Int).SetString(string(t), 10); ok {
+			e.encodeBigInt(n)
+		} else if f, err := strconv.ParseFloat(string(t), 64); err == nil {
+			e.encodeFloat(f)
+		} else {
+			return fmt.Errorf("cbor: invalid number literal '%v'", t)
+		}
+	case time.Time:
+		e.writeHead(cborMajorTag, 1)
+		if t.Nanosecond() == 0 {
+			e.encodeInt(t.Unix())
+		} else {
+			e.encodeFloat(float64(t.UnixNano()) / 1e9)
+		}
+	case CBORTag:
+		e.writeHead(cborMajorTag, t.Number)
+		return e.encode(t.Content)
+	case CBORSimple:
+		if t < 24 {
+			e.b = append(e.b, cborMajorSimple<<5|byte(t))
+		} else {
+			e.b = append(e.b, cborMajorSimple<<5|24, byte(t))
+		}
+	case []interface{}:
+		e.writeHead(cborMajorArray, uint64(len(t)))
+		for _, ele := range t {
+			if err := e.encode(ele); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		type entry struct {
+			key   []byte
+			value interface{}
+		}
+		entries := make([]entry, 0, len(t))
+		for k, v := range t {
+			var keyEnc cborEncoder
+			_ = keyEnc.encode(k)
+			entries = append(entries, entry{key: keyEnc.b, value: v})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i].key, entries[j].key) < 0
+		})
+		e.writeHead(cborMajorMap, uint64(len(t)))
+		for _, ent := range entries {
+			e.b = append(e.b, ent.key...)
+			if err := e.encode(ent.value); err != nil {
+				return err
+			}
+		}
+	default:
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		return e.encode(generic)
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
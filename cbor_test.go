@@ -0,0 +1,192 @@
+package gabs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestCBORDecode(t *testing.T) {
+	type testCase struct {
+		input  string
+		output string
+	}
+	// Examples taken from RFC 8949 Appendix A.
+	tests := []testCase{
+		{input: "00", output: `0`},
+		{input: "17", output: `23`},
+		{input: "1818", output: `24`},
+		{input: "1903e8", output: `1000`},
+		{input: "1b000000e8d4a51000", output: `1000000000000`},
+		{input: "1bffffffffffffffff", output: `18446744073709551615`},
+		{input: "3bffffffffffffffff", output: `-18446744073709551616`},
+		{input: "20", output: `-1`},
+		{input: "3903e7", output: `-1000`},
+		{input: "f90000", output: `0`},
+		{input: "f93c00", output: `1`},
+		{input: "f93e00", output: `1.5`},
+		{input: "f97bff", output: `65504`},
+		{input: "fa47c35000", output: `100000`},
+		{input: "fb3ff199999999999a", output: `1.1`},
+		{input: "f90001", output: `5.960464477539063e-8`},
+		{input: "f4", output: `false`},
+		{input: "f5", output: `true`},
+		{input: "f6", output: `null`},
+		{input: "f7", output: `null`},
+		{input: "6449455446", output: `"IETF"`},
+		{input: "62c3bc", output: `"ü"`},
+		{input: "83010203", output: `[1,2,3]`},
+		{input: "8301820203820405", output: `[1,[2,3],[4,5]]`},
+		{input: "a201020304", output: `{"1":2,"3":4}`},
+		{input: "a26161016162820203", output: `{"a":1,"b":[2,3]}`},
+		{input: "7f657374726561646d696e67ff", output: `"streaming"`},
+		{input: "9f018202039f0405ffff", output: `[1,[2,3],[4,5]]`},
+		{input: "bf61610161629f0203ffff", output: `{"a":1,"b":[2,3]}`},
+		{input: "c074323031332d30332d32315432303a30343a30305a", output: `"2013-03-21T20:04:00Z"`},
+		{input: "c11a514b67b0", output: `"2013-03-21T20:04:00Z"`},
+		{input: "c1fb41d452d9ec200000", output: `"2013-03-21T20:04:00.5Z"`},
+		{input: "c249010000000000000000", output: `18446744073709551616`},
+		{input: "c349010000000000000000", output: `-18446744073709551617`},
+		{input: "d74401020304", output: `{"Number":23,"Content":"AQIDBA=="}`},
+		{input: "d9d9f7f5", output: `true`},
+		{input: "f0", output: `16`},
+	}
+
+	for i, test := range tests {
+		b, err := hex.DecodeString(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := ParseCBOR(b)
+		if err != nil {
+			t.Errorf("[%d] Failed to parse: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, c.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestCBORDecodeTypes(t *testing.T) {
+	b, _ := hex.DecodeString("a2616244010203046174c11a514b67b0")
+	c, err := ParseCBOR(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal([]byte{1, 2, 3, 4}, c.Path("b").Data().([]byte)) {
+		t.Errorf("Wrong bytes: %v", c.Path("b").Data())
+	}
+	if exp, act := time.Unix(1363896240, 0).UTC(), c.Path("t").Data().(time.Time); !exp.Equal(act) {
+		t.Errorf("Wrong time: %v != %v", act, exp)
+	}
+
+	b, _ = hex.DecodeString("c249010000000000000000")
+	if c, err = ParseCBOR(b); err != nil {
+		t.Fatal(err)
+	}
+	exp, _ := new(big.Int).SetString("18446744073709551616", 10)
+	if act, ok := c.Data().(*big.Int); !ok || act.Cmp(exp) != 0 {
+		t.Errorf("Wrong bignum: %v != %v", c.Data(), exp)
+	}
+}
+
+func TestCBOREncode(t *testing.T) {
+	big1, _ := new(big.Int).SetString("18446744073709551616", 10)
+	big2, _ := new(big.Int).SetString("-18446744073709551617", 10)
+
+	type testCase struct {
+		input  interface{}
+		output string
+	}
+	tests := []testCase{
+		{input: float64(0), output: "00"},
+		{input: float64(24), output: "1818"},
+		{input: 1000, output: "1903e8"},
+		{input: -1000, output: "3903e7"},
+		{input: uint64(18446744073709551615), output: "1bffffffffffffffff"},
+		{input: 1.5, output: "f93e00"},
+		{input: 65504.0, output: "19ffe0"},
+		{input: 0.5, output: "f93800"},
+		{input: 100000.5, output: "fa47c35040"},
+		{input: 1.1, output: "fb3ff199999999999a"},
+		{input: 5.960464477539063e-8, output: "f90001"},
+		{input: math.Inf(1), output: "f97c00"},
+		{input: math.Inf(-1), output: "f9fc00"},
+		{input: math.NaN(), output: "f97e00"},
+		{input: math.Copysign(0, -1), output: "f98000"},
+		{input: json.Number("18446744073709551616"), output: "c249010000000000000000"},
+		{input: big1, output: "c249010000000000000000"},
+		{input: big2, output: "c349010000000000000000"},
+		{input: json.Number("-5"), output: "24"},
+		{input: "IETF", output: "6449455446"},
+		{input: []byte{1, 2, 3, 4}, output: "4401020304"},
+		{input: nil, output: "f6"},
+		{input: true, output: "f5"},
+		{input: []interface{}{1, []interface{}{2, 3}}, output: "8201820203"},
+		{input: map[string]interface{}{"aa": 1, "b": 2, "a": 3}, output: "a3616103616202626161" + "01"},
+		{input: time.Unix(1363896240, 0), output: "c11a514b67b0"},
+		{input: CBORTag{Number: 23, Content: []byte{1}}, output: "d74101"},
+		{input: CBORSimple(16), output: "f0"},
+		{input: CBORSimple(255), output: "f8ff"},
+	}
+
+	for i, test := range tests {
+		b, err := Wrap(test.input).CBOR()
+		if err != nil {
+			t.Errorf("[%d] Failed to encode: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, hex.EncodeToString(b); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestCBORRoundTrip(t *testing.T) {
+	c, err := ParseJSON([]byte(`{"a":[1,-2,3.5,"four",null,true],"b":{"c":{"d":1e300}},"e":""}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := c.CBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ParseCBOR(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := c.String(), decoded.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func TestCBORDecodeErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"18",
+		"830102",
+		"6449",
+		"1c",
+		"ff",
+		"0001",
+		"5f4101620203ff",
+		"62c328",
+		"c06161",
+		"c24101ff",
+		"f818",
+	}
+	for i, test := range tests {
+		b, err := hex.DecodeString(test)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ParseCBOR(b); err == nil {
+			t.Errorf("[%d] Expected error for %v", i, test)
+		}
+	}
+}
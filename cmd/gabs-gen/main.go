@@ -0,0 +1,101 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Command gabs-gen generates Go struct definitions with json tags from sample
+// JSON documents. Samples are read from each file argument, or from stdin when
+// there are none, and each input may contain any number of concatenated or
+// newline delimited documents. The shapes of all samples are merged into a
+// single set of types.
+//
+// Usage:
+//
+//	gabs-gen [-type Root] [-package main] [-o types.go] [file ...]
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Jeffail/gabs/v2"
+)
+
+func readSamples(r io.Reader) ([]*gabs.Container, error) {
+	var samples []*gabs.Container
+	dec := json.NewDecoder(r)
+	for {
+		sample, err := gabs.ParseJSONDecoder(dec)
+		if err == io.EOF {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+}
+
+func run() error {
+	typeName := flag.String("type", "Root", "The name of the root type")
+	packageName := flag.String("package", "main", "The package name of the generated file")
+	output := flag.String("o", "", "A file to write to instead of stdout")
+	flag.Parse()
+
+	var samples []*gabs.Container
+	if flag.NArg() == 0 {
+		var err error
+		if samples, err = readSamples(os.Stdin); err != nil {
+			return fmt.Errorf("stdin: %w", err)
+		}
+	}
+	for _, path := range flag.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		fileSamples, err := readSamples(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+		samples = append(samples, fileSamples...)
+	}
+
+	src, err := gabs.GenerateStructs(samples, gabs.GenerateOptTypeName(*typeName), gabs.GenerateOptPackage(*packageName))
+	if err != nil {
+		return err
+	}
+	src = append([]byte("// Code generated by gabs-gen. DO NOT EDIT.\n\n"), src...)
+
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0o644)
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "gabs-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+//------------------------------------------------------------------------------
+
+// CSVOpt is a functional option for the ParseCSV function.
+type CSVOpt func(o *csvOpts)
+
+type csvOpts struct {
+	comma       rune
+	inferTypes  bool
+	expandPaths bool
+}
+
+// CSVOptComma sets the field delimiter, which defaults to ','. Use '\t' in
+// order to parse TSV.
+func CSVOptComma(r rune) CSVOpt {
+	return func(o *csvOpts) {
+		o.comma = r
+	}
+}
+
+// CSVOptInferTypes converts fields that are valid JSON numbers into float64
+// values, the fields "true" and "false" into booleans and empty fields into
+// null. All other fields remain strings.
+func CSVOptInferTypes() CSVOpt {
+	return func(o *csvOpts) {
+		o.inferTypes = true
+	}
+}
+
+// CSVOptExpandPaths treats header names as dot notation paths, and expands
+// them into nested objects with SetP. Nested objects whose keys are exactly the
+// indexes 0 to n-1 are then converted into arrays, which reverses the column
+// names produced by the CSV method.
+func CSVOptExpandPaths() CSVOpt {
+	return func(o *csvOpts) {
+		o.expandPaths = true
+	}
+}
+
+// ParseCSV parses CSV data into an array of objects, where the first record is
+// a header providing the keys of each object. By default all values are
+// strings and header names are used as keys verbatim.
+func ParseCSV(r io.Reader, opts ...CSVOpt) (*Container, error) {
+	o := csvOpts{comma: ','}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = o.comma
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			err = errors.New("csv: missing header record")
+		}
+		return nil, err
+	}
+
+	rows := []interface{}{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := New()
+		for i, field := range record {
+			var value interface{} = field
+			if o.inferTypes {
+				value = inferCSVType(field)
+			}
+			if o.expandPaths {
+				_, err = row.SetP(value, header[i])
+			} else {
+				_, err = row.Set(value, header[i])
+			}
+			if err != nil {
+				return nil, fmt.Errorf("csv: failed to set column '%v' of row %v: %w", header[i], len(rows)+1, err)
+			}
+		}
+		if o.expandPaths {
+			obj := row.Data().(map[string]interface{})
+			for k, v := range obj {
+				obj[k] = csvExpandArrays(v)
+			}
+		}
+		rows = append(rows, row.Data())
+	}
+	return &Container{object: rows}, nil
+}
+
+// csvExpandArrays converts objects nested within a value that have keys
+// covering exactly the indexes 0 to n-1 into arrays.
+func csvExpandArrays(v interface{}) interface{} {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	for k, e := range obj {
+		obj[k] = csvExpandArrays(e)
+	}
+	arr := make([]interface{}, len(obj))
+	for k, e := range obj {
+		index, err := strconv.Atoi(k)
+		if err != nil || index < 0 || index >= len(arr) || strconv.Itoa(index) != k {
+			return obj
+		}
+		arr[index] = e
+	}
+	return arr
+}
+
+func inferCSVType(field string) interface{} {
+	switch field {
+	case "":
+		return nil
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	var f float64
+	if json.Unmarshal([]byte(field), &f) == nil {
+		return f
+	}
+	return field
+}
+
+//------------------------------------------------------------------------------
+
+// CSV writes an array of objects to w as CSV data, with a header record
+// followed by a record for each object. Objects are flattened with Flatten,
+// and therefore nested fields are written to columns named by their dot
+// notation paths.
+//
+// When columns are provided only those columns are written, in the order
+// given. Otherwise the columns are the sorted union of all fields within the
+// array. Fields that are missing or null are written as empty values.
+func (g *Container) CSV(w io.Writer, columns ...string) error {
+	return g.writeDelimited(w, ',', columns)
+}
+
+// TSV writes an array of objects to w as TSV data following the same rules as
+// CSV.
+func (g *Container) TSV(w io.Writer, columns ...string) error {
+	return g.writeDelimited(w, '\t', columns)
+}
+
+func (g *Container) writeDelimited(w io.Writer, comma rune, columns []string) error {
+	arr, ok := g.Data().([]interface{})
+	if !ok {
+		return ErrNotArray
+	}
+
+	rows := make([]map[string]interface{}, len(arr))
+	for i, e := range arr {
+		if _, isObj := e.(map[string]interface{}); !isObj {
+			return fmt.Errorf("csv: element %v is not an object", i)
+		}
+		flat, err := (&Container{object: e}).Flatten()
+		if err != nil {
+			return err
+		}
+		rows[i] = flat
+	}
+
+	if len(columns) == 0 {
+		seen := map[string]struct{}{}
+		for _, row := range rows {
+			for k := range row {
+				if _, exists := seen[k]; !exists {
+					seen[k] = struct{}{}
+					columns = append(columns, k)
+				}
+			}
+		}
+		sort.Strings(columns)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, col := range columns {
+			field, err := csvField(row[col])
+			if err != nil {
+				return fmt.Errorf("csv: failed to write column '%v': %w", col, err)
+			}
+			record[i] = field
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvField(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return t, nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	case json.Number:
+		return t.String(), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+//------------------------------------------------------------------------------
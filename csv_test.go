@@ -0,0 +1,133 @@
+package gabs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	type testCase struct {
+		input  string
+		opts   []CSVOpt
+		output string
+	}
+	tests := []testCase{
+		{
+			input:  "a,b\n",
+			output: `[]`,
+		},
+		{
+			input:  "a,b\n1,x\n2,\"y,z\"\n",
+			output: `[{"a":"1","b":"x"},{"a":"2","b":"y,z"}]`,
+		},
+		{
+			input:  "a,b.c\n1,x\n",
+			output: `[{"a":"1","b.c":"x"}]`,
+		},
+		{
+			input:  "a,b.c,b.d\n1,x,\n",
+			opts:   []CSVOpt{CSVOptExpandPaths()},
+			output: `[{"a":"1","b":{"c":"x","d":""}}]`,
+		},
+		{
+			input:  "a.0,a.1.b,a.2,c.0,c.2,d.00,d.1,e\n1,x,y,2,3,4,5,6\n",
+			opts:   []CSVOpt{CSVOptExpandPaths()},
+			output: `[{"a":["1",{"b":"x"},"y"],"c":{"0":"2","2":"3"},"d":{"00":"4","1":"5"},"e":"6"}]`,
+		},
+		{
+			input:  "n,f,b,s,e,z\n10,-1.5e3,true,hello,,007\n",
+			opts:   []CSVOpt{CSVOptInferTypes()},
+			output: `[{"b":true,"e":null,"f":-1500,"n":10,"s":"hello","z":"007"}]`,
+		},
+		{
+			input:  "a\tb\n1\t2\n",
+			opts:   []CSVOpt{CSVOptComma('\t'), CSVOptInferTypes()},
+			output: `[{"a":1,"b":2}]`,
+		},
+	}
+
+	for i, test := range tests {
+		c, err := ParseCSV(strings.NewReader(test.input), test.opts...)
+		if err != nil {
+			t.Errorf("[%d] Failed to parse: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, c.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+
+	for i, test := range []string{"", "a,b\n1\n", "a,a.b\n1,2\n"} {
+		if _, err := ParseCSV(strings.NewReader(test), CSVOptExpandPaths()); err == nil {
+			t.Errorf("[%d] Expected error from '%v'", i, test)
+		}
+	}
+}
+
+func TestCSV(t *testing.T) {
+	type testCase struct {
+		input   string
+		columns []string
+		output  string
+	}
+	tests := []testCase{
+		{
+			input:  `[]`,
+			output: "\n",
+		},
+		{
+			input:  `[{"a":1,"b":{"c":"x,y","d":[true,null]}},{"a":2.5,"e":"z"}]`,
+			output: "a,b.c,b.d.0,b.d.1,e\n1,\"x,y\",true,,\n2.5,,,,z\n",
+		},
+		{
+			input:   `[{"a":1,"b":{"c":"x"}},{"a":2}]`,
+			columns: []string{"b.c", "a", "missing"},
+			output:  "b.c,a,missing\nx,1,\n,2,\n",
+		},
+	}
+
+	for i, test := range tests {
+		c, err := ParseJSON([]byte(test.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err = c.CSV(&buf, test.columns...); err != nil {
+			t.Errorf("[%d] Failed to write: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, buf.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %q != %q", i, act, exp)
+		}
+	}
+
+	for i, test := range []string{`{}`, `[1]`, `[{"a":1},"b"]`} {
+		c, err := ParseJSON([]byte(test))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = c.CSV(&bytes.Buffer{}); err == nil {
+			t.Errorf("[%d] Expected error from '%v'", i, test)
+		}
+	}
+}
+
+func TestTSVRoundTrip(t *testing.T) {
+	c, err := ParseJSON([]byte(`[{"id":1,"user":{"name":"a\tb","admin":true},"tags":["x",{"y":[2,3]}]},{"id":2,"user":{"name":"c"}}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = c.TSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseCSV(&buf, CSVOptComma('\t'), CSVOptInferTypes(), CSVOptExpandPaths())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `[{"id":1,"tags":["x",{"y":[2,3]}],"user":{"admin":true,"name":"a\tb"}},{"id":2,"tags":[null,{"y":[null,null]}],"user":{"admin":null,"name":"c"}}]`, parsed.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
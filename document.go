@@ -0,0 +1,552 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+//------------------------------------------------------------------------------
+
+// Document is a JSON, JSONC or JSON5 document that is edited in place. Unlike
+// a Container, which discards formatting when parsed, a Document retains the
+// original text of the input and edits only replace the spans of text that
+// they affect. Whitespace, comments, key order and number literals elsewhere
+// in the document are therefore preserved.
+type Document struct {
+	src        []byte
+	root       *docNode
+	indentUnit string
+}
+
+// ParseDocument parses a JSON, JSONC or JSON5 document for editing.
+func ParseDocument(src []byte) (*Document, error) {
+	d := &Document{}
+	if err := d.reset(append([]byte{}, src...)); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Bytes returns the current text of the document.
+func (d *Document) Bytes() []byte {
+	return append([]byte{}, d.src...)
+}
+
+// String returns the current text of the document.
+func (d *Document) String() string {
+	return string(d.src)
+}
+
+// Container parses the current text of the document into a *Container
+// following the same rules as ParseJSON5. Modifying the returned container
+// does not modify the document.
+func (d *Document) Container() (*Container, error) {
+	return ParseJSON5(d.src)
+}
+
+// Set sets the value of a field located by a hierarchy of field names,
+// following the same rules as Container.Set. When the field already exists
+// only the text of its value is replaced, otherwise a new member or element is
+// inserted after the last one of its parent, matching the layout of its
+// siblings. The value is serialized as JSON.
+func (d *Document) Set(value interface{}, hierarchy ...string) error {
+	if len(hierarchy) == 0 {
+		text, err := d.render(value, d.root.start)
+		if err != nil {
+			return err
+		}
+		return d.apply(docEdit{d.root.start, d.root.end, text})
+	}
+
+	node := d.root
+	for i, seg := range hierarchy {
+		last := i == len(hierarchy)-1
+		switch {
+		case node.isObject:
+			idx := node.memberIndex(seg)
+			if idx < 0 {
+				return d.insertMember(node, seg, docNested(value, hierarchy[i+1:]))
+			}
+			child := node.members[idx].value
+			if last || (!child.isObject && !child.isArray && d.isNull(child)) {
+				text, err := d.render(docNested(value, hierarchy[i+1:]), child.start)
+				if err != nil {
+					return err
+				}
+				return d.apply(docEdit{child.start, child.end, text})
+			}
+			node = child
+		case node.isArray:
+			if seg == "-" {
+				if i == 0 {
+					return errors.New("unable to append new array index at root of path")
+				}
+				return d.insertElement(node, docNested(value, hierarchy[i+1:]))
+			}
+			index, err := strconv.Atoi(seg)
+			if err != nil {
+				return fmt.Errorf("failed to resolve path segment '%v': found array but segment value '%v' could not be parsed into array index: %v", i, seg, err)
+			}
+			if index < 0 || index >= len(node.members) {
+				return fmt.Errorf("failed to resolve path segment '%v': found array but index '%v' exceeded target array size of '%v'", i, seg, len(node.members))
+			}
+			child := node.members[index].value
+			if last {
+				text, err := d.render(value, child.start)
+				if err != nil {
+					return err
+				}
+				return d.apply(docEdit{child.start, child.end, text})
+			}
+			node = child
+		default:
+			return ErrPathCollision
+		}
+	}
+	return nil
+}
+
+// SetP sets the value of a field at a path using dot notation, following the
+// same rules as Set.
+func (d *Document) SetP(value interface{}, path string) error {
+	return d.Set(value, DotPathToSlice(path)...)
+}
+
+// Delete removes a field located by a hierarchy of field names, along with its
+// separating comma and, when it occupies its own lines, those lines. Returns
+// ErrNotFound if the field does not exist.
+func (d *Document) Delete(hierarchy ...string) error {
+	if len(hierarchy) == 0 {
+		return ErrInvalidQuery
+	}
+	node := d.root
+	for i, seg := range hierarchy {
+		idx := -1
+		switch {
+		case node.isObject:
+			idx = node.memberIndex(seg)
+		case node.isArray:
+			index, err := strconv.Atoi(seg)
+			if err == nil && index >= 0 && index < len(node.members) {
+				idx = index
+			}
+		}
+		if idx < 0 {
+			return ErrNotFound
+		}
+		if i == len(hierarchy)-1 {
+			return d.apply(d.removeMember(node, idx)...)
+		}
+		node = node.members[idx].value
+	}
+	return nil
+}
+
+// DeleteP removes a field at a path using dot notation, following the same
+// rules as Delete.
+func (d *Document) DeleteP(path string) error {
+	return d.Delete(DotPathToSlice(path)...)
+}
+
+//------------------------------------------------------------------------------
+
+type docNode struct {
+	start, end int
+	isObject   bool
+	isArray    bool
+	members    []docMember
+}
+
+// docMember is either an object member or an array element.
+type docMember struct {
+	key       string
+	keyQuoted bool
+	start     int
+	colonEnd  int
+	value     *docNode
+	comma     int
+}
+
+func (n *docNode) memberIndex(key string) int {
+	// Later duplicate keys take precedence, just as they do when parsed.
+	for i := len(n.members) - 1; i >= 0; i-- {
+		if n.members[i].key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+type docEdit struct {
+	start, end int
+	text       string
+}
+
+func docNested(value interface{}, hierarchy []string) interface{} {
+	for i := len(hierarchy) - 1; i >= 0; i-- {
+		value = map[string]interface{}{hierarchy[i]: value}
+	}
+	return value
+}
+
+// apply performs a set of non-overlapping edits and parses the result, the
+// document is left unchanged if the result is invalid.
+func (d *Document) apply(edits ...docEdit) error {
+	var buf bytes.Buffer
+	last := 0
+	for _, e := range sortDocEdits(edits) {
+		buf.Write(d.src[last:e.start])
+		buf.WriteString(e.text)
+		last = e.end
+	}
+	buf.Write(d.src[last:])
+	return d.reset(buf.Bytes())
+}
+
+func sortDocEdits(edits []docEdit) []docEdit {
+	for i := 1; i < len(edits); i++ {
+		for j := i; j > 0 && edits[j].start < edits[j-1].start; j-- {
+			edits[j], edits[j-1] = edits[j-1], edits[j]
+		}
+	}
+	return edits
+}
+
+func (d *Document) reset(src []byte) error {
+	p := docParser{json5Parser{src: src}}
+	if bytes.HasPrefix(src, []byte("\uFEFF")) {
+		p.pos = 3
+	}
+	if err := p.skipSpace(); err != nil {
+		return err
+	}
+	root, err := p.parseNode(0)
+	if err != nil {
+		return err
+	}
+	if err = p.skipSpace(); err != nil {
+		return err
+	}
+	if !p.eof() {
+		return p.errorf("unexpected character %q after top level value", p.peekRune())
+	}
+	d.src, d.root = src, root
+	d.indentUnit = docDetectIndent(src)
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+func docDetectIndent(src []byte) string {
+	for _, line := range bytes.Split(src, []byte("\n")) {
+		trimmed := bytes.TrimLeft(line, " \t")
+		if len(trimmed) > 0 && len(trimmed) < len(line) {
+			return string(line[:len(line)-len(trimmed)])
+		}
+	}
+	return "  "
+}
+
+func (d *Document) lineStart(pos int) int {
+	return bytes.LastIndexByte(d.src[:pos], '\n') + 1
+}
+
+// lineIndent returns the leading whitespace of the line containing pos.
+func (d *Document) lineIndent(pos int) string {
+	start := d.lineStart(pos)
+	end := start
+	for end < len(d.src) && (d.src[end] == ' ' || d.src[end] == '\t') {
+		end++
+	}
+	return string(d.src[start:end])
+}
+
+func (d *Document) sameLine(a, b int) bool {
+	return bytes.IndexByte(d.src[a:b], '\n') < 0
+}
+
+func (d *Document) multiLine() bool {
+	return bytes.IndexByte(bytes.TrimSpace(d.src), '\n') >= 0
+}
+
+func (d *Document) isNull(n *docNode) bool {
+	return string(d.src[n.start:n.end]) == "null"
+}
+
+// render serializes a value to be written at pos, where objects and arrays
+// are indented relative to the line of pos when the document spans multiple
+// lines.
+func (d *Document) render(value interface{}, pos int) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if d.multiLine() {
+		enc.SetIndent(d.lineIndent(pos), d.indentUnit)
+	}
+	if err := enc.Encode(value); err != nil {
+		return "", err
+	}
+	return string(bytes.TrimRight(buf.Bytes(), "\n")), nil
+}
+
+func (d *Document) renderKey(n *docNode, key string) string {
+	if len(n.members) > 0 && !n.members[len(n.members)-1].keyQuoted {
+		if id, err := (&json5Parser{src: []byte(key)}).parseIdentifier(); err == nil && id == key {
+			return key
+		}
+	}
+	b, _ := json.Marshal(key)
+	return string(b)
+}
+
+// restOfLineEnd returns the position following the end of the line containing
+// pos if the remainder of the line is only whitespace or a line comment,
+// otherwise -1.
+func (d *Document) restOfLineEnd(pos int) int {
+	for i := pos; i < len(d.src); i++ {
+		switch c := d.src[i]; {
+		case c == '\n':
+			return i + 1
+		case c == ' ' || c == '\t' || c == '\r':
+		case c == '/' && i+1 < len(d.src) && d.src[i+1] == '/':
+			if end := bytes.IndexByte(d.src[i:], '\n'); end >= 0 {
+				return i + end + 1
+			}
+			return len(d.src)
+		default:
+			return -1
+		}
+	}
+	return len(d.src)
+}
+
+func (d *Document) insertMember(n *docNode, key string, value interface{}) error {
+	return d.insert(n, d.renderKey(n, key)+d.colonText(n), value)
+}
+
+func (d *Document) insertElement(n *docNode, value interface{}) error {
+	return d.insert(n, "", value)
+}
+
+func (d *Document) colonText(n *docNode) string {
+	if len(n.members) > 0 {
+		m := n.members[len(n.members)-1]
+		if sep := d.src[m.colonEnd-1 : m.value.start]; len(bytes.TrimSpace(sep)) == 1 && !bytes.ContainsAny(sep, "\n") {
+			return string(sep)
+		}
+	}
+	if d.multiLine() {
+		return ": "
+	}
+	return ":"
+}
+
+// insert adds a new member after the last member of an object or array, where
+// prefix is the key and separator of an object member.
+func (d *Document) insert(n *docNode, prefix string, value interface{}) error {
+	if len(n.members) == 0 {
+		open := n.start + 1
+		if !d.multiLine() {
+			text, err := d.render(value, open)
+			if err != nil {
+				return err
+			}
+			return d.apply(docEdit{open, n.end - 1, prefix + text})
+		}
+		indent := d.lineIndent(n.start)
+		childIndent := indent + d.indentUnit
+		text, err := d.render(value, open)
+		if err != nil {
+			return err
+		}
+		text = string(bytes.ReplaceAll([]byte(text), []byte("\n"+indent), []byte("\n"+childIndent)))
+		return d.apply(docEdit{open, n.end - 1, "\n" + childIndent + prefix + text + "\n" + indent})
+	}
+
+	last := n.members[len(n.members)-1]
+	if d.sameLine(n.start, last.start) {
+		// Members are laid out inline, so copy the separator between the last
+		// two members, or follow the style of the key separator.
+		sep := ", "
+		if len(n.members) > 1 {
+			prev := n.members[len(n.members)-2]
+			if gap := d.src[prev.comma+1 : last.start]; len(bytes.TrimSpace(gap)) == 0 && !bytes.ContainsAny(gap, "\n") {
+				sep = "," + string(gap)
+			}
+		} else if prefix != "" && !bytes.HasSuffix([]byte(d.colonText(n)), []byte(" ")) {
+			sep = ","
+		}
+		text, err := d.render(value, last.start)
+		if err != nil {
+			return err
+		}
+		if last.comma >= 0 {
+			return d.apply(docEdit{last.comma + 1, last.comma + 1, sep[1:] + prefix + text + ","})
+		}
+		return d.apply(docEdit{last.value.end, last.value.end, sep + prefix + text})
+	}
+
+	indent := d.lineIndent(last.start)
+	text, err := d.render(value, last.start)
+	if err != nil {
+		return err
+	}
+	member := "\n" + indent + prefix + text
+	if last.comma >= 0 {
+		// The document uses trailing commas, which the new member inherits.
+		if end := d.restOfLineEnd(last.comma + 1); end > 0 && d.src[end-1] == '\n' {
+			return d.apply(docEdit{end - 1, end - 1, member + ","})
+		}
+		return d.apply(docEdit{last.comma + 1, last.comma + 1, member + ","})
+	}
+	if end := d.restOfLineEnd(last.value.end); end > 0 && d.src[end-1] == '\n' {
+		return d.apply(docEdit{last.value.end, last.value.end, ","}, docEdit{end - 1, end - 1, member})
+	}
+	return d.apply(docEdit{last.value.end, last.value.end, "," + member})
+}
+
+// removeMember returns the edits required to remove a member of an object or
+// array.
+func (d *Document) removeMember(n *docNode, idx int) []docEdit {
+	m := n.members[idx]
+	end := m.value.end
+	if m.comma >= 0 {
+		end = m.comma + 1
+	}
+
+	ls := d.lineStart(m.start)
+	if len(bytes.TrimSpace(d.src[ls:m.start])) == 0 && !d.sameLine(n.start, m.start) {
+		if lineEnd := d.restOfLineEnd(end); lineEnd > 0 {
+			edits := []docEdit{{ls, lineEnd, ""}}
+			if m.comma < 0 && idx > 0 {
+				prev := n.members[idx-1]
+				edits = append(edits, docEdit{prev.comma, prev.comma + 1, ""})
+			}
+			return edits
+		}
+	}
+
+	switch {
+	case m.comma >= 0:
+		for end < len(d.src) && (d.src[end] == ' ' || d.src[end] == '\t') {
+			end++
+		}
+		return []docEdit{{m.start, end, ""}}
+	case idx > 0:
+		return []docEdit{{n.members[idx-1].value.end, m.value.end, ""}}
+	}
+	return []docEdit{{m.start, m.value.end, ""}}
+}
+
+//------------------------------------------------------------------------------
+
+// docParser records the spans of values within a JSON5 document.
+type docParser struct {
+	json5Parser
+}
+
+func (p *docParser) parseNode(depth int) (*docNode, error) {
+	if depth > json5MaxDepth {
+		return nil, p.errorf("exceeded maximum nesting depth")
+	}
+	n := &docNode{start: p.pos}
+	switch p.peek() {
+	case '{':
+		n.isObject = true
+	case '[':
+		n.isArray = true
+	default:
+		if _, err := p.parseValue(depth); err != nil {
+			return nil, err
+		}
+		n.end = p.pos
+		return n, nil
+	}
+
+	closer := byte('}')
+	if n.isArray {
+		closer = ']'
+	}
+	p.pos++
+	for {
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		if p.peek() == closer {
+			p.pos++
+			n.end = p.pos
+			return n, nil
+		}
+
+		m := docMember{start: p.pos, comma: -1}
+		if n.isObject {
+			var err error
+			if c := p.peek(); c == '"' || c == '\'' {
+				m.keyQuoted = true
+				m.key, err = p.parseString()
+			} else {
+				m.key, err = p.parseIdentifier()
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err = p.skipSpace(); err != nil {
+				return nil, err
+			}
+			if p.peek() != ':' {
+				return nil, p.errorf("expected ':' after object key '%v'", m.key)
+			}
+			p.pos++
+			m.colonEnd = p.pos
+			if err = p.skipSpace(); err != nil {
+				return nil, err
+			}
+		}
+
+		var err error
+		if m.value, err = p.parseNode(depth + 1); err != nil {
+			return nil, err
+		}
+		if err = p.skipSpace(); err != nil {
+			return nil, err
+		}
+		switch p.peek() {
+		case ',':
+			m.comma = p.pos
+			p.pos++
+			n.members = append(n.members, m)
+		case closer:
+			n.members = append(n.members, m)
+		default:
+			if p.eof() {
+				return nil, p.errorf("unexpected end of input, '%c' is not closed", p.src[n.start])
+			}
+			return nil, p.errorf("expected ',' or '%c', found %q", closer, p.peekRune())
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
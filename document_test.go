@@ -0,0 +1,293 @@
+package gabs
+
+import (
+	"testing"
+)
+
+func TestDocumentSet(t *testing.T) {
+	type testCase struct {
+		input  string
+		path   string
+		value  interface{}
+		output string
+	}
+	tests := []testCase{
+		{
+			input:  `{"a": 1.50, "b": 2}`,
+			path:   "b",
+			value:  3,
+			output: `{"a": 1.50, "b": 3}`,
+		},
+		{
+			input:  `{"a":1}`,
+			path:   "b",
+			value:  "x",
+			output: `{"a":1,"b":"x"}`,
+		},
+		{
+			input:  `{"a": 1, "b": 2}`,
+			path:   "c",
+			value:  true,
+			output: `{"a": 1, "b": 2, "c": true}`,
+		},
+		{
+			input:  `{}`,
+			path:   "a.b",
+			value:  1,
+			output: `{"a":{"b":1}}`,
+		},
+		{
+			input:  `[1, 2]`,
+			path:   "1",
+			value:  "two",
+			output: `[1, "two"]`,
+		},
+		{
+			input:  `{"a": [1, 2]}`,
+			path:   "a.-",
+			value:  3,
+			output: `{"a": [1, 2, 3]}`,
+		},
+		{
+			input:  `{"a": null}`,
+			path:   "a.b",
+			value:  1,
+			output: `{"a": {"b":1}}`,
+		},
+		{
+			input: `// Service configuration
+{
+  // The port to listen on.
+  "port": 0x1F90, // hex is fine
+  "host": "localhost"
+}
+`,
+			path:  "host",
+			value: "0.0.0.0",
+			output: `// Service configuration
+{
+  // The port to listen on.
+  "port": 0x1F90, // hex is fine
+  "host": "0.0.0.0"
+}
+`,
+		},
+		{
+			input: `{
+  "port": 80 // default
+}
+`,
+			path:  "tls.enabled",
+			value: true,
+			output: `{
+  "port": 80, // default
+  "tls": {
+    "enabled": true
+  }
+}
+`,
+		},
+		{
+			input: `{
+	name: 'gabs',
+	tags: [
+		'a',
+	],
+}
+`,
+			path:  "version",
+			value: 2,
+			output: `{
+	name: 'gabs',
+	tags: [
+		'a',
+	],
+	version: 2,
+}
+`,
+		},
+		{
+			input: `{
+	"tags": [
+		"a"
+	]
+}
+`,
+			path:  "tags.-",
+			value: "b",
+			output: `{
+	"tags": [
+		"a",
+		"b"
+	]
+}
+`,
+		},
+		{
+			input: `{
+	"a": {}
+}
+`,
+			path:  "a.b",
+			value: []interface{}{1},
+			output: `{
+	"a": {
+		"b": [
+			1
+		]
+	}
+}
+`,
+		},
+	}
+
+	for i, test := range tests {
+		d, err := ParseDocument([]byte(test.input))
+		if err != nil {
+			t.Fatalf("[%d] Failed to parse: %v", i, err)
+		}
+		if err = d.SetP(test.value, test.path); err != nil {
+			t.Errorf("[%d] Failed to set: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, d.String(); exp != act {
+			t.Errorf("[%d] Wrong result:\n%v\n!=\n%v", i, act, exp)
+		}
+	}
+}
+
+func TestDocumentDelete(t *testing.T) {
+	type testCase struct {
+		input  string
+		path   string
+		output string
+	}
+	tests := []testCase{
+		{
+			input:  `{"a": 1, "b": 2, "c": 3}`,
+			path:   "b",
+			output: `{"a": 1, "c": 3}`,
+		},
+		{
+			input:  `{"a": 1, "b": 2}`,
+			path:   "b",
+			output: `{"a": 1}`,
+		},
+		{
+			input:  `{"a": 1}`,
+			path:   "a",
+			output: `{}`,
+		},
+		{
+			input:  `[1, 2, 3]`,
+			path:   "0",
+			output: `[2, 3]`,
+		},
+		{
+			input: `{
+  "a": 1, // about a
+  // about b
+  "b": 2
+}`,
+			path: "a",
+			output: `{
+  // about b
+  "b": 2
+}`,
+		},
+		{
+			input: `{
+  "a": 1,
+  "b": 2
+}`,
+			path: "b",
+			output: `{
+  "a": 1
+}`,
+		},
+		{
+			input: `{
+  "a": {
+    "b": 1,
+    "c": 2,
+  },
+}`,
+			path: "a.c",
+			output: `{
+  "a": {
+    "b": 1,
+  },
+}`,
+		},
+	}
+
+	for i, test := range tests {
+		d, err := ParseDocument([]byte(test.input))
+		if err != nil {
+			t.Fatalf("[%d] Failed to parse: %v", i, err)
+		}
+		if err = d.DeleteP(test.path); err != nil {
+			t.Errorf("[%d] Failed to delete: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, d.String(); exp != act {
+			t.Errorf("[%d] Wrong result:\n%v\n!=\n%v", i, act, exp)
+		}
+	}
+}
+
+func TestDocumentErrors(t *testing.T) {
+	d, err := ParseDocument([]byte(`{"a": 1, "b": [1]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.SetP(1, "a.b"); err != ErrPathCollision {
+		t.Errorf("Expected ErrPathCollision, received: %v", err)
+	}
+	if err = d.SetP(1, "b.5"); err == nil {
+		t.Error("Expected error from out of bounds index")
+	}
+	if err = d.DeleteP("c"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, received: %v", err)
+	}
+	if err = d.DeleteP("b.1"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, received: %v", err)
+	}
+	if exp, act := `{"a": 1, "b": [1]}`, d.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	if _, err = ParseDocument([]byte(`{"a": }`)); err == nil {
+		t.Error("Expected error from invalid document")
+	}
+}
+
+func TestDocumentContainer(t *testing.T) {
+	d, err := ParseDocument([]byte("{\n  // comment\n  \"a\": {\"b\": 1},\n}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.Set("c", "a", "c"); err != nil {
+		t.Fatal(err)
+	}
+	if err = d.Set(2, "a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	c, err := d.Container()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"a":{"b":2,"c":"c"}}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := "{\n  // comment\n  \"a\": {\"b\": 2, \"c\": \"c\"},\n}\n", d.String(); exp != act {
+		t.Errorf("Wrong result: %q != %q", act, exp)
+	}
+
+	if err = d.Set([]interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := "[]\n", d.String(); exp != act {
+		t.Errorf("Wrong result: %q != %q", act, exp)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+//------------------------------------------------------------------------------
+
+// EqualOpt is a functional option for the Equal and EqualAt functions.
+type EqualOpt func(e *equalOpts)
+
+type equalOpts struct {
+	unorderedArrays bool
+	ignorePaths     [][]string
+}
+
+// EqualOptUnorderedArrays treats arrays as unordered collections, where two
+// arrays are equal when each element of one can be paired with an equal
+// element of the other.
+func EqualOptUnorderedArrays() EqualOpt {
+	return func(e *equalOpts) {
+		e.unorderedArrays = true
+	}
+}
+
+// EqualOptIgnorePaths excludes fields at dot notation paths from the
+// comparison, a path segment '*' matches any object key or array index. Paths
+// are relative to the containers being compared.
+func EqualOptIgnorePaths(paths ...string) EqualOpt {
+	return func(e *equalOpts) {
+		for _, p := range paths {
+			e.ignorePaths = append(e.ignorePaths, DotPathToSlice(p))
+		}
+	}
+}
+
+// Equal returns true if two containers hold semantically equal JSON values.
+// Object keys are compared regardless of order and numbers are compared by
+// value regardless of their Go representation, therefore float64(1), int(1)
+// and json.Number("1") are all equal. Floats are compared by their shortest
+// decimal representation, such that a document parsed with and without
+// ParseOptUseNumber is equal to itself.
+//
+// Values that are not JSON types are compared with reflect.DeepEqual.
+func Equal(a, b *Container, opts ...EqualOpt) bool {
+	var e equalOpts
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return e.equal(nil, a.Data(), b.Data())
+}
+
+// EqualAt returns true if the values found at a dot notation path within two
+// containers are semantically equal according to the same rules as Equal. If
+// the path does not exist in either container the result is true, if it exists
+// in only one of them the result is false.
+func EqualAt(a, b *Container, path string, opts ...EqualOpt) bool {
+	aC, bC := a.Path(path), b.Path(path)
+	if aC == nil || bC == nil {
+		return aC == nil && bC == nil
+	}
+	return Equal(aC, bC, opts...)
+}
+
+//------------------------------------------------------------------------------
+
+func (e *equalOpts) ignored(path []string) bool {
+	return matchesAnyPath(e.ignorePaths, path)
+}
+
+// matchesAnyPath returns true if a path matches any of a list of path
+// patterns, where a pattern segment '*' matches any object key or array index.
+func matchesAnyPath(patterns [][]string, path []string) bool {
+pathLoop:
+	for _, p := range patterns {
+		if len(p) != len(path) {
+			continue
+		}
+		for i, seg := range p {
+			if seg != "*" && seg != path[i] {
+				continue pathLoop
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (e *equalOpts) child(path []string, seg string) []string {
+	if len(e.ignorePaths) == 0 {
+		return nil
+	}
+	return append(path[:len(path):len(path)], seg)
+}
+
+func (e *equalOpts) equal(path []string, a, b interface{}) bool {
+	switch aT := a.(type) {
+	case map[string]interface{}:
+		bT, ok := b.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if len(e.ignorePaths) == 0 && len(aT) != len(bT) {
+			return false
+		}
+		for k, aV := range aT {
+			childPath := e.child(path, k)
+			if e.ignored(childPath) {
+				continue
+			}
+			bV, exists := bT[k]
+			if !exists || !e.equal(childPath, aV, bV) {
+				return false
+			}
+		}
+		for k := range bT {
+			if _, exists := aT[k]; !exists && !e.ignored(e.child(path, k)) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bT, ok := b.([]interface{})
+		if !ok || len(aT) != len(bT) {
+			return false
+		}
+		if e.unorderedArrays {
+			return e.equalUnordered(path, aT, bT)
+		}
+		for i, aV := range aT {
+			childPath := e.child(path, strconv.Itoa(i))
+			if e.ignored(childPath) {
+				continue
+			}
+			if !e.equal(childPath, aV, bT[i]) {
+				return false
+			}
+		}
+		return true
+	case nil:
+		return b == nil
+	case string:
+		bT, ok := b.(string)
+		return ok && aT == bT
+	case bool:
+		bT, ok := b.(bool)
+		return ok && aT == bT
+	}
+	if aN, ok := toRat(a); ok {
+		bN, ok := toRat(b)
+		return ok && aN.Cmp(bN) == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func (e *equalOpts) equalUnordered(path []string, a, b []interface{}) bool {
+	matched := make([]bool, len(b))
+	for i, aV := range a {
+		childPath := e.child(path, strconv.Itoa(i))
+		if e.ignored(childPath) {
+			continue
+		}
+		found := false
+		for j, bV := range b {
+			if !matched[j] && e.equal(childPath, aV, bV) {
+				matched[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+//------------------------------------------------------------------------------
+
+// toRat attempts to convert a numeric value of any supported representation
+// into an exact rational. Floats are interpreted as the shortest decimal that
+// represents them, which is the literal they were most likely parsed from, and
+// therefore float64(0.1) is converted to exactly 1/10 and equals the
+// json.Number "0.1". Returns false if the value is not a number or is a
+// non-finite float.
+func toRat(v interface{}) (*big.Rat, bool) {
+	switch t := v.(type) {
+	case float64:
+		if math.IsInf(t, 0) || math.IsNaN(t) {
+			return nil, false
+		}
+		return new(big.Rat).SetString(strconv.FormatFloat(t, 'g', -1, 64))
+	case float32:
+		if math.IsInf(float64(t), 0) || math.IsNaN(float64(t)) {
+			return nil, false
+		}
+		return new(big.Rat).SetString(strconv.FormatFloat(float64(t), 'g', -1, 32))
+	case int:
+		return new(big.Rat).SetInt64(int64(t)), true
+	case int8:
+		return new(big.Rat).SetInt64(int64(t)), true
+	case int16:
+		return new(big.Rat).SetInt64(int64(t)), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(t)), true
+	case int64:
+		return new(big.Rat).SetInt64(t), true
+	case uint:
+		return new(big.Rat).SetUint64(uint64(t)), true
+	case uint8:
+		return new(big.Rat).SetUint64(uint64(t)), true
+	case uint16:
+		return new(big.Rat).SetUint64(uint64(t)), true
+	case uint32:
+		return new(big.Rat).SetUint64(uint64(t)), true
+	case uint64:
+		return new(big.Rat).SetUint64(t), true
+	case json.Number:
+		return new(big.Rat).SetString(string(t))
+	case *big.Int:
+		return new(big.Rat).SetInt(t), true
+	case *big.Rat:
+		return new(big.Rat).Set(t), true
+	case *big.Float:
+		if t.IsInf() {
+			return nil, false
+		}
+		r, _ := t.Rat(nil)
+		return r, true
+	}
+	return nil, false
+}
+
+//------------------------------------------------------------------------------
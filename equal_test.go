@@ -0,0 +1,107 @@
+package gabs
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	type testCase struct {
+		a, b  interface{}
+		opts  []EqualOpt
+		equal bool
+	}
+	parse := func(s string) interface{} {
+		c, err := ParseJSON([]byte(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c.Data()
+	}
+	tests := []testCase{
+		{a: parse(`{"a":1,"b":[1,2]}`), b: parse(`{"b":[1,2],"a":1}`), equal: true},
+		{a: parse(`{"a":1}`), b: map[string]interface{}{"a": 1}, equal: true},
+		{a: parse(`{"a":1}`), b: map[string]interface{}{"a": json.Number("1.0")}, equal: true},
+		{a: parse(`{"a":1}`), b: map[string]interface{}{"a": uint8(1)}, equal: true},
+		{a: parse(`{"a":1.5}`), b: map[string]interface{}{"a": float32(1.5)}, equal: true},
+		{a: parse(`{"a":0.1}`), b: map[string]interface{}{"a": json.Number("0.1")}, equal: true},
+		{a: parse(`{"a":[1.1,2.2]}`), b: map[string]interface{}{"a": []interface{}{json.Number("1.1"), json.Number("2.20")}}, equal: true},
+		{a: parse(`{"a":0.1}`), b: map[string]interface{}{"a": json.Number("0.10000000000000001")}, equal: false},
+		{a: parse(`{"a":1}`), b: map[string]interface{}{"a": int64(2)}, equal: false},
+		{a: parse(`{"a":1}`), b: map[string]interface{}{"a": "1"}, equal: false},
+		{a: parse(`{"a":1}`), b: parse(`{"a":1,"b":null}`), equal: false},
+		{a: parse(`{"a":null}`), b: parse(`{"a":null}`), equal: true},
+		{a: parse(`[1,2,3]`), b: parse(`[3,2,1]`), equal: false},
+		{a: parse(`[1,2,3]`), b: parse(`[3,2,1]`), opts: []EqualOpt{EqualOptUnorderedArrays()}, equal: true},
+		{a: parse(`[1,1,2]`), b: parse(`[1,2,2]`), opts: []EqualOpt{EqualOptUnorderedArrays()}, equal: false},
+		{a: parse(`[{"a":[1,2]},{"b":1}]`), b: parse(`[{"b":1},{"a":[2,1]}]`), opts: []EqualOpt{EqualOptUnorderedArrays()}, equal: true},
+		{
+			a:     parse(`{"id":1,"meta":{"updated":"yesterday","v":1}}`),
+			b:     parse(`{"id":1,"meta":{"updated":"today","v":1}}`),
+			opts:  []EqualOpt{EqualOptIgnorePaths("meta.updated")},
+			equal: true,
+		},
+		{
+			a:     parse(`{"id":1,"meta":{"v":1}}`),
+			b:     parse(`{"id":1,"meta":{"updated":"today","v":1}}`),
+			opts:  []EqualOpt{EqualOptIgnorePaths("meta.updated")},
+			equal: true,
+		},
+		{
+			a:     parse(`{"items":[{"id":1,"ts":1},{"id":2,"ts":2}]}`),
+			b:     parse(`{"items":[{"id":1,"ts":3},{"id":2,"ts":4}]}`),
+			opts:  []EqualOpt{EqualOptIgnorePaths("items.*.ts")},
+			equal: true,
+		},
+		{
+			a:     parse(`{"items":[{"id":1,"ts":1},{"id":2,"ts":2}]}`),
+			b:     parse(`{"items":[{"id":1,"ts":3},{"id":3,"ts":4}]}`),
+			opts:  []EqualOpt{EqualOptIgnorePaths("items.*.ts")},
+			equal: false,
+		},
+		{a: []interface{}{"a", true, nil}, b: parse(`["a",true,null]`), equal: true},
+		{a: []string{"a"}, b: []string{"a"}, equal: true},
+	}
+
+	for i, test := range tests {
+		if exp, act := test.equal, Equal(Wrap(test.a), Wrap(test.b), test.opts...); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+		if exp, act := test.equal, Equal(Wrap(test.b), Wrap(test.a), test.opts...); exp != act {
+			t.Errorf("[%d] Wrong reversed result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestEqualAt(t *testing.T) {
+	a, err := ParseJSON([]byte(`{"foo":{"bar":[1,2]},"baz":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := New()
+	if _, err = b.SetP([]interface{}{int64(1), json.Number("2")}, "foo.bar"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = b.SetP(2, "baz"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !EqualAt(a, b, "foo") {
+		t.Error("Expected foo to be equal")
+	}
+	if EqualAt(a, b, "baz") {
+		t.Error("Expected baz to differ")
+	}
+	if !EqualAt(a, b, "nope") {
+		t.Error("Expected missing paths to be equal")
+	}
+	if !EqualAt(a, b, "foo.bar.1") {
+		t.Error("Expected array element to be equal")
+	}
+	if _, err = b.SetP(nil, "qux"); err != nil {
+		t.Fatal(err)
+	}
+	if EqualAt(a, b, "qux") {
+		t.Error("Expected missing and null to differ")
+	}
+}
@@ -130,6 +130,15 @@ func DotPathToSlice(path string) []string {
 // Container references a specific element within a wrapped structure.
 type Container struct {
 	object interface{}
+
+	// lazy is set when the wrapped structure was parsed with ParseJSONLazy
+	// and may contain values that have not yet been decoded, and holds the
+	// options for decoding them.
+	lazy *parseOpts
+
+	// parse holds the options for decoding into the container with
+	// UnmarshalJSON or UnmarshalText.
+	parse *parseOpts
 }
 
 // Data returns the underlying value of the target element in the wrapped
@@ -138,40 +147,45 @@ func (g *Container) Data() interface{} {
 	if g == nil {
 		return nil
 	}
+	if g.lazy != nil {
+		g.object = g.lazy.decodeLazy(g.object)
+		g.lazy = nil
+	}
+	return g.object
+}
+
+// node returns the underlying value of the target element, where values of a
+// lazily parsed structure are decoded only one level deep.
+func (g *Container) node() interface{} {
+	if g == nil {
+		return nil
+	}
+	if _, isLazy := g.object.(lazyJSON); isLazy && g.lazy != nil {
+		g.object = g.lazy.expandLazy(g.object)
+	}
 	return g.object
 }
 
+// child wraps a value that belongs to the target element.
+func (g *Container) child(v interface{}) *Container {
+	return &Container{object: v, lazy: g.lazy, parse: g.parse}
+}
+
 //------------------------------------------------------------------------------
 
 func (g *Container) searchStrict(allowWildcard bool, hierarchy ...string) (*Container, error) {
-	object := g.Data()
+	object := g.node()
 	for target := 0; target < len(hierarchy); target++ {
 		pathSeg := hierarchy[target]
 		switch typedObj := object.(type) {
 		case map[string]interface{}:
-			var ok bool
-			if object, ok = typedObj[pathSeg]; !ok {
+			if _, ok := typedObj[pathSeg]; !ok {
 				return nil, fmt.Errorf("failed to resolve path segment '%v': key '%v' was not found", target, pathSeg)
 			}
+			object = g.expandField(typedObj, pathSeg)
 		case []interface{}:
 			if allowWildcard && pathSeg == "*" {
-				var tmpArray []interface{}
-				if (target + 1) >= len(hierarchy) {
-					tmpArray = typedObj
-				} else {
-					tmpArray = make([]interface{}, 0, len(typedObj))
-					for _, val := range typedObj {
-						if res := Wrap(val).Search(hierarchy[target+1:]...); res != nil {
-							tmpArray = append(tmpArray, res.Data())
-						}
-					}
-				}
-
-				if len(tmpArray) == 0 {
-					return nil, nil
-				}
-
-				return &Container{tmpArray}, nil
+				return g.searchWildcard(typedObj, hierarchy[target+1:]), nil
 			}
 			index, err := strconv.Atoi(pathSeg)
 			if err != nil {
@@ -183,12 +197,43 @@ func (g *Container) searchStrict(allowWildcard bool, hierarchy ...string) (*Cont
 			if len(typedObj) <= index {
 				return nil, fmt.Errorf("failed to resolve path segment '%v': found array but index '%v' exceeded target array size of '%v'", target, pathSeg, len(typedObj))
 			}
-			object = typedObj[index]
+			object = g.expandElement(typedObj, index)
 		default:
-			return nil, fmt.Errorf("failed to resolve path segment '%v': field '%v' was not found", target, pathSeg)
+			if allowWildcard && pathSeg == "*" {
+				if elems, ok := reflectElements(object); ok {
+					return g.searchWildcard(elems, hierarchy[target+1:]), nil
+				}
+			}
+			var err error
+			if object, err = reflectSearch(object, pathSeg); err != nil {
+				return nil, fmt.Errorf("failed to resolve path segment '%v': %v", target, err)
+			}
+		}
+	}
+	return g.child(object), nil
+}
+
+// searchWildcard searches each element of an array with the remaining search
+// hierarchy and returns the results within an array, or nil if there were no
+// results.
+func (g *Container) searchWildcard(array []interface{}, hierarchy []string) *Container {
+	var tmpArray []interface{}
+	if len(hierarchy) == 0 {
+		tmpArray = array
+	} else {
+		tmpArray = make([]interface{}, 0, len(array))
+		for i := range array {
+			if res := g.child(g.expandElement(array, i)).Search(hierarchy...); res != nil {
+				tmpArray = append(tmpArray, res.Data())
+			}
 		}
 	}
-	return &Container{object}, nil
+
+	if len(tmpArray) == 0 {
+		return nil
+	}
+
+	return g.child(tmpArray)
 }
 
 // Search attempts to find and return an object within the wrapped structure by
@@ -198,6 +243,9 @@ func (g *Container) searchStrict(allowWildcard bool, hierarchy ...string) (*Cont
 // either a an integer which is interpreted as the index of the target, or the
 // character '*', in which case all elements are searched with the remaining
 // search hierarchy and the results returned within an array.
+//
+// Typed Go values such as map[string]string, []string or structs are navigated
+// with reflection, where struct fields are located by their json tag names.
 func (g *Container) Search(hierarchy ...string) *Container {
 	c, _ := g.searchStrict(true, hierarchy...)
 	return c
@@ -244,45 +292,76 @@ func (g *Container) ExistsP(path string) bool {
 }
 
 // Index attempts to find and return an element within a JSON array by an index.
+// Typed Go slices and arrays are also supported.
 func (g *Container) Index(index int) *Container {
-	if array, ok := g.Data().([]interface{}); ok {
+	if array, ok := g.node().([]interface{}); ok {
 		if index >= len(array) {
 			return nil
 		}
-		return &Container{array[index]}
+		return g.child(g.expandElement(array, index))
+	}
+	if elems, ok := reflectElements(g.node()); ok {
+		if index < 0 || index >= len(elems) {
+			return nil
+		}
+		return g.child(elems[index])
 	}
 	return nil
 }
 
 // Children returns a slice of all children of an array element. This also works
 // for objects, however, the children returned for an object will be in a random
-// order and you lose the names of the returned objects this way. If the
-// underlying container value isn't an array or map nil is returned.
+// order and you lose the names of the returned objects this way. Typed Go
+// slices, maps and structs are navigated with reflection in the same way as
+// Search. If the underlying container value isn't an array or map nil is
+// returned.
 func (g *Container) Children() []*Container {
-	if array, ok := g.Data().([]interface{}); ok {
+	if array, ok := g.node().([]interface{}); ok {
 		children := make([]*Container, len(array))
 		for i := 0; i < len(array); i++ {
-			children[i] = &Container{array[i]}
+			children[i] = g.child(g.expandElement(array, i))
 		}
 		return children
 	}
-	if mmap, ok := g.Data().(map[string]interface{}); ok {
+	if mmap, ok := g.node().(map[string]interface{}); ok {
 		children := make([]*Container, 0, len(mmap))
-		for _, obj := range mmap {
-			children = append(children, &Container{obj})
+		for name := range mmap {
+			children = append(children, g.child(g.expandField(mmap, name)))
+		}
+		return children
+	}
+	if elems, ok := reflectElements(g.node()); ok {
+		children := make([]*Container, len(elems))
+		for i, e := range elems {
+			children[i] = g.child(e)
+		}
+		return children
+	}
+	if fields, ok := reflectFields(g.node()); ok {
+		children := make([]*Container, 0, len(fields))
+		for _, f := range fields {
+			children = append(children, g.child(f))
 		}
 		return children
 	}
 	return nil
 }
 
-// ChildrenMap returns a map of all the children of an object element. IF the
-// underlying value isn't a object then an empty map is returned.
+// ChildrenMap returns a map of all the children of an object element, which can
+// also be a typed Go map or struct. IF the underlying value isn't a object then
+// an empty map is returned.
 func (g *Container) ChildrenMap() map[string]*Container {
-	if mmap, ok := g.Data().(map[string]interface{}); ok {
+	if mmap, ok := g.node().(map[string]interface{}); ok {
 		children := make(map[string]*Container, len(mmap))
-		for name, obj := range mmap {
-			children[name] = &Container{obj}
+		for name := range mmap {
+			children[name] = g.child(g.expandField(mmap, name))
+		}
+		return children
+	}
+	if fields, ok := reflectFields(g.node()); ok {
+		children := make(map[string]*Container, len(fields))
+		for name, f := range fields {
+			children[name] = g.child(f)
 		}
 		return children
 	}
@@ -299,6 +378,10 @@ func (g *Container) ChildrenMap() map[string]*Container {
 // Any parts of the hierarchy that do not exist will be constructed as objects.
 // This includes parts that could be interpreted as array indexes.
 //
+// Typed Go values are navigated with reflection, and can be set as long as the
+// value is convertible to the target type without a loss of precision. Struct
+// fields and array elements can only be set when reached through a pointer.
+//
 // Returns a container of the new value or an error.
 func (g *Container) Set(value interface{}, hierarchy ...string) (*Container, error) {
 	if g == nil {
@@ -308,10 +391,11 @@ func (g *Container) Set(value interface{}, hierarchy ...string) (*Container, err
 		g.object = value
 		return g, nil
 	}
-	if g.object == nil {
+	object := g.node()
+	if object == nil {
 		g.object = map[string]interface{}{}
+		object = g.object
 	}
-	object := g.object
 
 	for target := 0; target < len(hierarchy); target++ {
 		pathSeg := hierarchy[target]
@@ -320,7 +404,7 @@ func (g *Container) Set(value interface{}, hierarchy ...string) (*Container, err
 			if target == len(hierarchy)-1 {
 				object = value
 				typedObj[pathSeg] = object
-			} else if object = typedObj[pathSeg]; object == nil {
+			} else if object = g.expandField(typedObj, pathSeg); object == nil {
 				typedObj[pathSeg] = map[string]interface{}{}
 				object = typedObj[pathSeg]
 			}
@@ -352,15 +436,19 @@ func (g *Container) Set(value interface{}, hierarchy ...string) (*Container, err
 				if target == len(hierarchy)-1 {
 					object = value
 					typedObj[index] = object
-				} else if object = typedObj[index]; object == nil {
+				} else if object = g.expandElement(typedObj, index); object == nil {
 					return nil, fmt.Errorf("failed to resolve path segment '%v': field '%v' was not found", target, pathSeg)
 				}
 			}
 		default:
-			return nil, ErrPathCollision
+			res, err := reflectSet(object, target, value, hierarchy[target:]...)
+			if err != nil {
+				return nil, err
+			}
+			return g.child(res), nil
 		}
 	}
-	return &Container{object}, nil
+	return g.child(object), nil
 }
 
 // SetP sets the value of a field at a path using dot notation, any parts
@@ -372,12 +460,12 @@ func (g *Container) SetP(value interface{}, path string) (*Container, error) {
 
 // SetIndex attempts to set a value of an array element based on an index.
 func (g *Container) SetIndex(value interface{}, index int) (*Container, error) {
-	if array, ok := g.Data().([]interface{}); ok {
+	if array, ok := g.node().([]interface{}); ok {
 		if index >= len(array) {
 			return nil, ErrOutOfBounds
 		}
 		array[index] = value
-		return &Container{array[index]}, nil
+		return g.child(array[index]), nil
 	}
 	return nil, ErrNotArray
 }
@@ -454,6 +542,10 @@ func (g *Container) ArrayOfSizeI(size, index int) (*Container, error) {
 // Delete an element at a path, an error is returned if the element does not
 // exist or is not an object. In order to remove an array element please use
 // ArrayRemove.
+//
+// Keys of typed Go maps are deleted in place, and elements of typed Go slices
+// are removed by setting a copy of the slice without them. Struct fields cannot
+// be deleted.
 func (g *Container) Delete(hierarchy ...string) error {
 	if g == nil || g.object == nil {
 		return ErrNotObj
@@ -462,10 +554,10 @@ func (g *Container) Delete(hierarchy ...string) error {
 		return ErrInvalidQuery
 	}
 
-	object := g.object
+	object := g.node()
 	target := hierarchy[len(hierarchy)-1]
 	if len(hierarchy) > 1 {
-		object = g.Search(hierarchy[:len(hierarchy)-1]...).Data()
+		object = g.Search(hierarchy[:len(hierarchy)-1]...).node()
 	}
 
 	if obj, ok := object.(map[string]interface{}); ok {
@@ -493,7 +585,15 @@ func (g *Container) Delete(hierarchy ...string) error {
 		g.Set(array, hierarchy[:len(hierarchy)-1]...)
 		return nil
 	}
-	return ErrNotObjOrArray
+	shortened, err := reflectDelete(object, target)
+	if err != nil || shortened == nil {
+		return err
+	}
+	if len(hierarchy) < 2 {
+		return errors.New("unable to delete array index at root of path")
+	}
+	_, err = g.Set(shortened, hierarchy[:len(hierarchy)-1]...)
+	return err
 }
 
 // DeleteP deletes an element at a path using dot notation, an error is returned
@@ -583,12 +683,21 @@ then reassign with Set.
 // ArrayAppend attempts to append a value onto a JSON array at a path. If the
 // target is not a JSON array then it will be converted into one, with its
 // original contents set to the first element of the array.
+//
+// When the target is a typed Go slice the value is appended to it if it is
+// convertible to the element type, otherwise the slice is converted into a JSON
+// array. The result is set following the same rules as Set.
 func (g *Container) ArrayAppend(value interface{}, hierarchy ...string) error {
-	if array, ok := g.Search(hierarchy...).Data().([]interface{}); ok {
+	target := g.Search(hierarchy...).node()
+	if array, ok := target.([]interface{}); ok {
 		array = append(array, value)
 		_, err := g.Set(array, hierarchy...)
 		return err
 	}
+	if appended, ok := reflectAppend(target, value); ok {
+		_, err := g.Set(appended, hierarchy...)
+		return err
+	}
 
 	newArray := []interface{}{}
 	if d := g.Search(hierarchy...).Data(); d != nil {
@@ -658,7 +767,7 @@ func (g *Container) ArrayRemove(index int, hierarchy ...string) error {
 	if index < 0 {
 		return ErrOutOfBounds
 	}
-	array, ok := g.Search(hierarchy...).Data().([]interface{})
+	array, ok := g.Search(hierarchy...).node().([]interface{})
 	if !ok {
 		return ErrNotArray
 	}
@@ -683,12 +792,15 @@ func (g *Container) ArrayElement(index int, hierarchy ...string) (*Container, er
 	if index < 0 {
 		return nil, ErrOutOfBounds
 	}
-	array, ok := g.Search(hierarchy...).Data().([]interface{})
+	target := g.Search(hierarchy...).node()
+	array, ok := target.([]interface{})
 	if !ok {
-		return nil, ErrNotArray
+		if array, ok = reflectElements(target); !ok {
+			return nil, ErrNotArray
+		}
 	}
 	if index < len(array) {
-		return &Container{array[index]}, nil
+		return g.child(g.expandElement(array, index)), nil
 	}
 	return nil, ErrOutOfBounds
 }
@@ -701,9 +813,13 @@ func (g *Container) ArrayElementP(index int, path string) (*Container, error) {
 
 // ArrayCount counts the number of elements in a JSON array at a path.
 func (g *Container) ArrayCount(hierarchy ...string) (int, error) {
-	if array, ok := g.Search(hierarchy...).Data().([]interface{}); ok {
+	target := g.Search(hierarchy...).node()
+	if array, ok := target.([]interface{}); ok {
 		return len(array), nil
 	}
+	if elems, ok := reflectElements(target); ok {
+		return len(elems), nil
+	}
 	return 0, ErrNotArray
 }
 
@@ -723,14 +839,7 @@ func walkObject(path string, obj, flat map[string]interface{}, includeEmpty bool
 		if len(path) > 0 {
 			elePath = path + "." + elePath
 		}
-		switch t := v.(type) {
-		case map[string]interface{}:
-			walkObject(elePath, t, flat, includeEmpty)
-		case []interface{}:
-			walkArray(elePath, t, flat, includeEmpty)
-		default:
-			flat[elePath] = t
-		}
+		walkValue(elePath, v, flat, includeEmpty)
 	}
 }
 
@@ -743,13 +852,25 @@ func walkArray(path string, arr []interface{}, flat map[string]interface{}, incl
 		if len(path) > 0 {
 			elePath = path + "." + elePath
 		}
-		switch t := ele.(type) {
-		case map[string]interface{}:
-			walkObject(elePath, t, flat, includeEmpty)
-		case []interface{}:
-			walkArray(elePath, t, flat, includeEmpty)
-		default:
-			flat[elePath] = t
+		walkValue(elePath, ele, flat, includeEmpty)
+	}
+}
+
+// walkValue flattens a value of any type, where typed Go maps, slices and
+// structs are walked in the same way as objects and arrays.
+func walkValue(path string, v interface{}, flat map[string]interface{}, includeEmpty bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		walkObject(path, t, flat, includeEmpty)
+	case []interface{}:
+		walkArray(path, t, flat, includeEmpty)
+	default:
+		if elems, ok := reflectElements(t); ok {
+			walkArray(path, elems, flat, includeEmpty)
+		} else if fields, ok := reflectFields(t); ok {
+			walkObject(path, fields, flat, includeEmpty)
+		} else {
+			flat[path] = t
 		}
 	}
 }
@@ -762,6 +883,9 @@ func walkArray(path string, arr []interface{}, flat map[string]interface{}, incl
 // object: `{"foo.0.bar":"1","foo.1.bar":"2"}`. `{"foo": [{"bar":[]},{"bar":{}}]}`
 // would flatten into the object `{}`
 //
+// Typed Go slices, maps and structs are flattened in the same way as arrays and
+// objects.
+//
 // Returns an error if the target is not a JSON object or array.
 func (g *Container) Flatten() (map[string]interface{}, error) {
 	return g.flatten(false)
@@ -782,7 +906,13 @@ func (g *Container) FlattenIncludeEmpty() (map[string]interface{}, error) {
 
 func (g *Container) flatten(includeEmpty bool) (map[string]interface{}, error) {
 	flattened := map[string]interface{}{}
-	switch t := g.Data().(type) {
+	data := g.Data()
+	if elems, ok := reflectElements(data); ok {
+		data = elems
+	} else if fields, ok := reflectFields(data); ok {
+		data = fields
+	}
+	switch t := data.(type) {
 	case map[string]interface{}:
 		walkObject("", t, flattened, includeEmpty)
 	case []interface{}:
@@ -797,7 +927,7 @@ func (g *Container) flatten(includeEmpty bool) (map[string]interface{}, error) {
 
 // Bytes marshals an element to a JSON []byte blob.
 func (g *Container) Bytes() []byte {
-	if data, err := json.Marshal(g.Data()); err == nil {
+	if data, err := json.Marshal(g.encodable()); err == nil {
 		return data
 	}
 	return []byte("null")
@@ -807,7 +937,7 @@ func (g *Container) Bytes() []byte {
 // and indent string.
 func (g *Container) BytesIndent(prefix, indent string) []byte {
 	if g.object != nil {
-		if data, err := json.MarshalIndent(g.Data(), prefix, indent); err == nil {
+		if data, err := json.MarshalIndent(g.encodable(), prefix, indent); err == nil {
 			return data
 		}
 	}
@@ -864,20 +994,35 @@ func (g *Container) EncodeJSON(encodeOpts ...EncodeOpt) []byte {
 
 // New creates a new gabs JSON object.
 func New() *Container {
-	return &Container{map[string]interface{}{}}
+	return &Container{object: map[string]interface{}{}}
+}
+
+// NewWithOpts creates a new gabs JSON object with parse options, which are
+// applied when the container is decoded with UnmarshalJSON or UnmarshalText.
+// This allows a container that is a field of a struct to be decoded with
+// ParseOptUseNumber.
+func NewWithOpts(opts ...ParseOpt) *Container {
+	return &Container{object: map[string]interface{}{}, parse: newParseOpts(opts)}
 }
 
 // Wrap an already unmarshalled JSON object (or a new map[string]interface{})
 // into a *Container.
 func Wrap(root interface{}) *Container {
-	return &Container{root}
+	return &Container{object: root}
 }
 
 // ParseJSON unmarshals a JSON byte slice into a *Container.
 func ParseJSON(sample []byte) (*Container, error) {
-	var gabs Container
+	return ParseJSONWithOpts(sample)
+}
+
+// ParseJSONWithOpts unmarshals a JSON byte slice into a *Container with parse
+// options. By default numbers are parsed into float64 values, which can be
+// changed with ParseOptUseNumber.
+func ParseJSONWithOpts(sample []byte, opts ...ParseOpt) (*Container, error) {
+	gabs := Container{parse: newParseOpts(opts)}
 
-	if err := json.Unmarshal(sample, &gabs.object); err != nil {
+	if err := gabs.parse.unmarshal(sample, &gabs.object); err != nil {
 		return nil, err
 	}
 
@@ -897,13 +1042,19 @@ func ParseJSONDecoder(decoder *json.Decoder) (*Container, error) {
 
 // ParseJSONFile reads a file and unmarshals the contents into a *Container.
 func ParseJSONFile(path string) (*Container, error) {
+	return ParseJSONFileWithOpts(path)
+}
+
+// ParseJSONFileWithOpts reads a file and unmarshals the contents into a
+// *Container with parse options.
+func ParseJSONFileWithOpts(path string, opts ...ParseOpt) (*Container, error) {
 	if len(path) > 0 {
 		cBytes, err := os.ReadFile(path)
 		if err != nil {
 			return nil, err
 		}
 
-		container, err := ParseJSON(cBytes)
+		container, err := ParseJSONWithOpts(cBytes, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -915,8 +1066,17 @@ func ParseJSONFile(path string) (*Container, error) {
 
 // ParseJSONBuffer reads a buffer and unmarshals the contents into a *Container.
 func ParseJSONBuffer(buffer io.Reader) (*Container, error) {
-	var gabs Container
+	return ParseJSONBufferWithOpts(buffer)
+}
+
+// ParseJSONBufferWithOpts reads a buffer and unmarshals the contents into a
+// *Container with parse options.
+func ParseJSONBufferWithOpts(buffer io.Reader, opts ...ParseOpt) (*Container, error) {
+	gabs := Container{parse: newParseOpts(opts)}
 	jsonDecoder := json.NewDecoder(buffer)
+	if gabs.parse.useNumber {
+		jsonDecoder.UseNumber()
+	}
 	if err := jsonDecoder.Decode(&gabs.object); err != nil {
 		return nil, err
 	}
@@ -928,7 +1088,34 @@ func ParseJSONBuffer(buffer io.Reader) (*Container, error) {
 // structs which contain Container instances to be marshaled using
 // json.Marshal().
 func (g *Container) MarshalJSON() ([]byte, error) {
-	return json.Marshal(g.Data())
+	return json.Marshal(g.encodable())
+}
+
+// UnmarshalJSON parses a JSON document into the container, replacing its
+// contents. This allows structs which contain Container instances to be
+// unmarshaled using json.Unmarshal(). Numbers are parsed according to the
+// options that the container was created with.
+func (g *Container) UnmarshalJSON(data []byte) error {
+	var object interface{}
+	if err := g.parse.unmarshal(data, &object); err != nil {
+		return err
+	}
+	g.object, g.lazy = object, nil
+	return nil
+}
+
+// MarshalText returns the JSON encoding of this container, implementing
+// encoding.TextMarshaler.
+func (g *Container) MarshalText() ([]byte, error) {
+	return g.MarshalJSON()
+}
+
+// UnmarshalText parses a JSON document into the container following the same
+// rules as UnmarshalJSON, implementing encoding.TextUnmarshaler. This allows a
+// container to be populated from flags, environment variables and other
+// textual configuration.
+func (g *Container) UnmarshalText(text []byte) error {
+	return g.UnmarshalJSON(text)
 }
 
 //------------------------------------------------------------------------------
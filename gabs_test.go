@@ -2,6 +2,7 @@ package gabs
 
 import (
 	"bytes"
+	"encoding"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -1646,7 +1647,7 @@ func TestBadIndexes(t *testing.T) {
 }
 
 func TestNilSet(t *testing.T) {
-	obj := Container{nil}
+	obj := Container{object: nil}
 	if _, err := obj.Set("bar", "foo"); err != nil {
 		t.Error(err)
 	}
@@ -1811,6 +1812,69 @@ func TestMarshalsJSON(t *testing.T) {
 	}
 }
 
+func TestUnmarshalJSON(t *testing.T) {
+	type request struct {
+		ID      string     `json:"id"`
+		Body    *Container `json:"body"`
+		Meta    Container  `json:"meta"`
+		Missing *Container `json:"missing"`
+	}
+
+	var req request
+	if err := json.Unmarshal([]byte(`{"id":"foo","body":{"a":[1,2]},"meta":{"b":true}}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := 2.0, req.Body.Path("a.1").Data(); exp != act {
+		t.Errorf("Unexpected result: %v != %v", act, exp)
+	}
+	if exp, act := true, req.Meta.Path("b").Data(); exp != act {
+		t.Errorf("Unexpected result: %v != %v", act, exp)
+	}
+	if req.Missing != nil {
+		t.Errorf("Unexpected result: %v", req.Missing)
+	}
+
+	marshaled, err := json.Marshal(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"id":"foo","body":{"a":[1,2]},"meta":{"b":true},"missing":null}`, string(marshaled); exp != act {
+		t.Errorf("Unexpected result: %v != %v", act, exp)
+	}
+
+	if err = json.Unmarshal([]byte(`{"body":{"a":}}`), &req); err == nil {
+		t.Error("Expected error from invalid JSON")
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	var _ encoding.TextMarshaler = &Container{}
+	var _ encoding.TextUnmarshaler = &Container{}
+
+	c := New()
+	if err := c.UnmarshalText([]byte(`{"foo":["bar"]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := "bar", c.Path("foo.0").Data(); exp != act {
+		t.Errorf("Unexpected result: %v != %v", act, exp)
+	}
+
+	text, err := c.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"foo":["bar"]}`, string(text); exp != act {
+		t.Errorf("Unexpected result: %v != %v", act, exp)
+	}
+
+	if err = c.UnmarshalText([]byte(`not json`)); err == nil {
+		t.Error("Expected error from invalid JSON")
+	}
+	if exp, act := `{"foo":["bar"]}`, c.String(); exp != act {
+		t.Errorf("Unexpected result: %v != %v", act, exp)
+	}
+}
+
 func TestFlatten(t *testing.T) {
 	type testCase struct {
 		input  string
@@ -0,0 +1,268 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//------------------------------------------------------------------------------
+
+// GenerateOpt is a functional option for the GenerateStructs function.
+type GenerateOpt func(o *generateOpts)
+
+type generateOpts struct {
+	typeName    string
+	packageName string
+}
+
+// GenerateOptTypeName sets the name of the type generated for the root of the
+// samples, which defaults to "Root". The types of nested objects are named by
+// appending their field names to the name of their parent.
+func GenerateOptTypeName(name string) GenerateOpt {
+	return func(o *generateOpts) {
+		o.typeName = name
+	}
+}
+
+// GenerateOptPackage causes the generated source to be a complete Go file with
+// a package clause of the given name and any imports that the types require.
+func GenerateOptPackage(name string) GenerateOpt {
+	return func(o *generateOpts) {
+		o.packageName = name
+	}
+}
+
+// GenerateStructs generates formatted Go type definitions with json tags that
+// are able to decode each of the provided sample documents. The shapes of the
+// samples are merged, where a field missing from any sample of an object is
+// generated as a pointer with the omitempty option, as is a field that is null
+// in any sample. Numbers are generated as int64 when every sample is an
+// integer and float64 otherwise, and strings are generated as time.Time when
+// every sample is an RFC 3339 timestamp. Values with conflicting types across
+// samples are generated as interface{}.
+func GenerateStructs(samples []*Container, opts ...GenerateOpt) ([]byte, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("at least one sample is required")
+	}
+	o := generateOpts{typeName: "Root"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	root := &sampleShape{}
+	for _, s := range samples {
+		root.merge(s.Data())
+	}
+
+	gen := &structGenerator{names: map[string]bool{}}
+	gen.reserve(o.typeName)
+	if root.category() == shapeKindObject {
+		gen.structDecl(root, o.typeName)
+	} else {
+		elemName := o.typeName
+		if root.category() == shapeKindArray {
+			elemName += "Element"
+		}
+		gen.decls = append([]string{
+			fmt.Sprintf("type %v %v\n", o.typeName, gen.typeOf(root, elemName)),
+		}, gen.decls...)
+	}
+
+	var buf bytes.Buffer
+	if o.packageName != "" {
+		fmt.Fprintf(&buf, "package %v\n\n", o.packageName)
+		if gen.usesTime {
+			buf.WriteString("import \"time\"\n\n")
+		}
+	}
+	buf.WriteString(strings.Join(gen.decls, "\n"))
+	return format.Source(buf.Bytes())
+}
+
+//------------------------------------------------------------------------------
+
+type structGenerator struct {
+	names    map[string]bool
+	decls    []string
+	usesTime bool
+}
+
+// reserve returns a unique type name based on name.
+func (gen *structGenerator) reserve(name string) string {
+	unique := name
+	for i := 2; gen.names[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	gen.names[unique] = true
+	return unique
+}
+
+func (gen *structGenerator) typeOf(s *sampleShape, name string) string {
+	switch s.category() {
+	case shapeKindBool:
+		return "bool"
+	case shapeKindInt:
+		return "int64"
+	case shapeKindFloat:
+		return "float64"
+	case shapeKindString:
+		if s.formats&stringFormatDateTime != 0 {
+			gen.usesTime = true
+			return "time.Time"
+		}
+		return "string"
+	case shapeKindObject:
+		return gen.structDecl(s, gen.reserve(name))
+	case shapeKindArray:
+		return "[]" + gen.typeOf(s.elem, name)
+	}
+	return "interface{}"
+}
+
+// structDecl generates the declaration of a struct type for an object shape,
+// where declarations of nested types follow their parent, and returns the
+// name of the type.
+func (gen *structGenerator) structDecl(s *sampleShape, name string) string {
+	index := len(gen.decls)
+	gen.decls = append(gen.decls, "")
+
+	keys := make([]string, 0, len(s.fields))
+	for k := range s.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "type %v struct {\n", name)
+	fieldNames := map[string]bool{}
+	for _, k := range keys {
+		if !validJSONTag(k) {
+			fmt.Fprintf(&buf, "\t// Field %q omitted as it cannot be expressed as a json tag.\n", k)
+			continue
+		}
+		f := s.fields[k]
+		fieldName := genFieldName(k)
+		for i := 2; fieldNames[fieldName]; i++ {
+			fieldName = genFieldName(k) + strconv.Itoa(i)
+		}
+		fieldNames[fieldName] = true
+
+		optional := f.present < s.objects
+		typeStr := gen.typeOf(f, name+fieldName)
+		switch f.category() {
+		case shapeKindBool, shapeKindInt, shapeKindFloat, shapeKindString, shapeKindObject:
+			if optional || f.kinds&shapeKindNull != 0 {
+				typeStr = "*" + typeStr
+			}
+		}
+
+		tag := k
+		if optional {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&buf, "\t%v %v `json:\"%v\"`\n", fieldName, typeStr, tag)
+	}
+	buf.WriteString("}\n")
+
+	gen.decls[index] = buf.String()
+	return name
+}
+
+//------------------------------------------------------------------------------
+
+var genInitialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DNS": true, "HTML": true,
+	"HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "TCP": true, "TTL": true, "UDP": true, "UI": true,
+	"URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// genFieldName converts an object key into an exported Go identifier, where
+// words separated by punctuation or case changes are capitalised and common
+// initialisms are upper cased.
+func genFieldName(key string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	runes := []rune(key)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]) {
+			flush()
+		}
+		word = append(word, r)
+	}
+	flush()
+
+	var name strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); genInitialisms[upper] {
+			name.WriteString(upper)
+			continue
+		}
+		r := []rune(w)
+		name.WriteRune(unicode.ToUpper(r[0]))
+		name.WriteString(string(r[1:]))
+	}
+
+	result := name.String()
+	if result == "" {
+		return "Field"
+	}
+	if first := []rune(result)[0]; !unicode.IsLetter(first) || !unicode.IsUpper(first) {
+		return "Field" + result
+	}
+	return result
+}
+
+// validJSONTag returns true if a key can be used as the name of a json struct
+// tag, following the same rules as encoding/json.
+func validJSONTag(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", r):
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			return false
+		}
+	}
+	return true
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,138 @@
+package gabs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateStructs(t *testing.T) {
+	type testCase struct {
+		samples []string
+		opts    []GenerateOpt
+		output  string
+	}
+	tests := []testCase{
+		{
+			samples: []string{`{"user_id":1,"name":"foo","score":1.5,"created":"2023-01-02T15:04:05Z","tags":["a"],"address":{"street":"main"}}`},
+			output: `type Root struct {
+	Address RootAddress ` + "`json:\"address\"`" + `
+	Created time.Time   ` + "`json:\"created\"`" + `
+	Name    string      ` + "`json:\"name\"`" + `
+	Score   float64     ` + "`json:\"score\"`" + `
+	Tags    []string    ` + "`json:\"tags\"`" + `
+	UserID  int64       ` + "`json:\"user_id\"`" + `
+}
+
+type RootAddress struct {
+	Street string ` + "`json:\"street\"`" + `
+}
+`,
+		},
+		{
+			samples: []string{
+				`{"a":1,"b":"x","c":{"d":true},"e":null,"f":[]}`,
+				`{"a":1.5,"b":"2023-01-02T15:04:05Z","e":null,"f":[1,"two"]}`,
+			},
+			output: `type Root struct {
+	A float64       ` + "`json:\"a\"`" + `
+	B string        ` + "`json:\"b\"`" + `
+	C *RootC        ` + "`json:\"c,omitempty\"`" + `
+	E interface{}   ` + "`json:\"e\"`" + `
+	F []interface{} ` + "`json:\"f\"`" + `
+}
+
+type RootC struct {
+	D bool ` + "`json:\"d\"`" + `
+}
+`,
+		},
+		{
+			samples: []string{`[{"id":1,"url":"x"},{"id":2,"parent_id":null}]`},
+			opts:    []GenerateOpt{GenerateOptTypeName("Items")},
+			output: `type Items []ItemsElement
+
+type ItemsElement struct {
+	ID       int64       ` + "`json:\"id\"`" + `
+	ParentID interface{} ` + "`json:\"parent_id,omitempty\"`" + `
+	URL      *string     ` + "`json:\"url,omitempty\"`" + `
+}
+`,
+		},
+		{
+			samples: []string{`{"when":"2023-01-02T15:04:05.123+01:00"}`},
+			opts:    []GenerateOpt{GenerateOptPackage("models")},
+			output: `package models
+
+import "time"
+
+type Root struct {
+	When time.Time ` + "`json:\"when\"`" + `
+}
+`,
+		},
+		{
+			samples: []string{`{"a":{"b":{"c":1}},"a_b":{"c":"x"},"fooBar":1,"foo-bar":2,"1st":true,"bad\"key":1}`},
+			output: `type Root struct {
+	Field1st bool    ` + "`json:\"1st\"`" + `
+	A        RootA   ` + "`json:\"a\"`" + `
+	AB       RootAB2 ` + "`json:\"a_b\"`" + `
+	// Field "bad\"key" omitted as it cannot be expressed as a json tag.
+	FooBar  int64 ` + "`json:\"foo-bar\"`" + `
+	FooBar2 int64 ` + "`json:\"fooBar\"`" + `
+}
+
+type RootA struct {
+	B RootAB ` + "`json:\"b\"`" + `
+}
+
+type RootAB struct {
+	C int64 ` + "`json:\"c\"`" + `
+}
+
+type RootAB2 struct {
+	C string ` + "`json:\"c\"`" + `
+}
+`,
+		},
+		{
+			samples: []string{`"text"`, `null`},
+			output: `type Root string
+`,
+		},
+	}
+
+	for i, test := range tests {
+		var samples []*Container
+		for _, s := range test.samples {
+			c, err := ParseJSON([]byte(s))
+			if err != nil {
+				t.Fatal(err)
+			}
+			samples = append(samples, c)
+		}
+		res, err := GenerateStructs(samples, test.opts...)
+		if err != nil {
+			t.Errorf("[%d] Unexpected error: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, string(res); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestGenerateStructsErrors(t *testing.T) {
+	if _, err := GenerateStructs(nil); err == nil {
+		t.Error("Expected error from no samples")
+	}
+	if _, err := GenerateStructs([]*Container{New()}, GenerateOptTypeName("not valid")); err == nil {
+		t.Error("Expected error from invalid type name")
+	}
+	res, err := GenerateStructs([]*Container{Wrap(map[string]interface{}{"n": 10})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(res), "N int64") {
+		t.Errorf("Unexpected result: %s", res)
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"hash"
+	"math"
+	"sort"
+	"strconv"
+)
+
+//------------------------------------------------------------------------------
+
+const (
+	hashTagNull byte = iota
+	hashTagBool
+	hashTagNumber
+	hashTagString
+	hashTagArray
+	hashTagObject
+)
+
+// HashNode is a node within a tree of Merkle style hashes, where the digest of
+// each object or array is derived from the digests of its children.
+type HashNode struct {
+	// Sum is the digest of the value at this node.
+	Sum []byte
+
+	// Children contains the nodes of each field of an object keyed by name, or
+	// each element of an array keyed by index. Children is nil for all other
+	// values.
+	Children map[string]*HashNode
+
+	tag byte
+}
+
+// Hash returns a stable digest of the value of the container computed with a
+// provided hash constructor such as sha256.New. The digest does not depend on
+// the iteration order of objects or the representation of numbers, therefore
+// documents that are equal according to Equal produce the same digest.
+//
+// Values that are not JSON types are converted by marshalling them as JSON, and
+// an error is returned if this fails.
+func (g *Container) Hash(newHash func() hash.Hash) ([]byte, error) {
+	h := hasher{newHash: newHash}
+	node, err := h.hash(g.Data(), false)
+	if err != nil {
+		return nil, err
+	}
+	return node.Sum, nil
+}
+
+// HashTree computes a digest for every node of the container in the same way
+// as Hash and returns them as a tree. The trees of two documents can be
+// compared with DiffHashTrees, which only walks branches that differ.
+func (g *Container) HashTree(newHash func() hash.Hash) (*HashNode, error) {
+	h := hasher{newHash: newHash}
+	return h.hash(g.Data(), true)
+}
+
+// DiffHashTrees compares two hash trees and returns the hierarchies of the
+// nodes that differ. Branches with matching digests are not visited, and when
+// a field only exists within one of the trees, or two nodes are of different
+// types, the hierarchy of that node is returned without descending further.
+//
+// Results are sorted in order to be deterministic.
+func DiffHashTrees(a, b *HashNode) [][]string {
+	var diffs [][]string
+	var walk func(path []string, a, b *HashNode)
+	walk = func(path []string, a, b *HashNode) {
+		if bytes.Equal(a.Sum, b.Sum) {
+			return
+		}
+		if a.Children == nil || b.Children == nil || a.tag != b.tag {
+			diffs = append(diffs, path)
+			return
+		}
+		for k, aChild := range a.Children {
+			childPath := append(path[:len(path):len(path)], k)
+			if bChild, exists := b.Children[k]; exists {
+				walk(childPath, aChild, bChild)
+			} else {
+				diffs = append(diffs, childPath)
+			}
+		}
+		for k := range b.Children {
+			if _, exists := a.Children[k]; !exists {
+				diffs = append(diffs, append(path[:len(path):len(path)], k))
+			}
+		}
+	}
+	if a == nil || b == nil {
+		if a != b {
+			diffs = append(diffs, []string{})
+		}
+		return diffs
+	}
+	walk([]string{}, a, b)
+	sort.Slice(diffs, func(i, j int) bool {
+		return lessHierarchy(diffs[i], diffs[j])
+	})
+	return diffs
+}
+
+func lessHierarchy(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		aIndex, aErr := strconv.Atoi(a[i])
+		bIndex, bErr := strconv.Atoi(b[i])
+		if aErr == nil && bErr == nil {
+			return aIndex < bIndex
+		}
+		return a[i] < b[i]
+	}
+	return len(a) < len(b)
+}
+
+//------------------------------------------------------------------------------
+
+type hasher struct {
+	newHash func() hash.Hash
+	lenBuf  [binary.MaxVarintLen64]byte
+}
+
+func (h *hasher) writeLen(w hash.Hash, l int) {
+	n := binary.PutUvarint(h.lenBuf[:], uint64(l))
+	_, _ = w.Write(h.lenBuf[:n])
+}
+
+func (h *hasher) hash(v interface{}, tree bool) (*HashNode, error) {
+	w := h.newHash()
+	node := &HashNode{}
+
+	switch t := v.(type) {
+	case nil:
+		node.tag = hashTagNull
+		_, _ = w.Write([]byte{hashTagNull})
+	case bool:
+		node.tag = hashTagBool
+		b := byte(0)
+		if t {
+			b = 1
+		}
+		_, _ = w.Write([]byte{hashTagBool, b})
+	case string:
+		node.tag = hashTagString
+		_, _ = w.Write([]byte{hashTagString})
+		h.writeLen(w, len(t))
+		_, _ = w.Write([]byte(t))
+	case []interface{}:
+		node.tag = hashTagArray
+		if tree {
+			node.Children = make(map[string]*HashNode, len(t))
+		}
+		_, _ = w.Write([]byte{hashTagArray})
+		h.writeLen(w, len(t))
+		for i, e := range t {
+			child, err := h.hash(e, tree)
+			if err != nil {
+				return nil, err
+			}
+			if tree {
+				node.Children[strconv.Itoa(i)] = child
+			}
+			_, _ = w.Write(child.Sum)
+		}
+	case map[string]interface{}:
+		node.tag = hashTagObject
+		if tree {
+			node.Children = make(map[string]*HashNode, len(t))
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		_, _ = w.Write([]byte{hashTagObject})
+		h.writeLen(w, len(t))
+		for _, k := range keys {
+			child, err := h.hash(t[k], tree)
+			if err != nil {
+				return nil, err
+			}
+			if tree {
+				node.Children[k] = child
+			}
+			h.writeLen(w, len(k))
+			_, _ = w.Write([]byte(k))
+			_, _ = w.Write(child.Sum)
+		}
+	default:
+		var literal string
+		if r, ok := toRat(v); ok {
+			literal = r.RatString()
+		} else if f, isFloat := v.(float64); isFloat && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			literal = strconv.FormatFloat(f, 'g', -1, 64)
+		} else {
+			generic, err := toGeneric(v)
+			if err != nil {
+				return nil, err
+			}
+			return h.hash(generic, tree)
+		}
+		node.tag = hashTagNumber
+		_, _ = w.Write([]byte{hashTagNumber})
+		h.writeLen(w, len(literal))
+		_, _ = w.Write([]byte(literal))
+	}
+
+	node.Sum = w.Sum(nil)
+	return node, nil
+}
+
+// toGeneric converts an arbitrary value into its generic JSON representation
+// by marshalling and then unmarshalling it.
+func toGeneric(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,138 @@
+package gabs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+)
+
+func TestHashStable(t *testing.T) {
+	a, err := ParseJSON([]byte(`{"a":1,"b":[1.5,"two",null,true],"c":{"d":{}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := Wrap(map[string]interface{}{
+		"c": map[string]interface{}{"d": map[string]interface{}{}},
+		"b": []interface{}{json.Number("1.50"), "two", nil, true},
+		"a": int64(1),
+	})
+
+	aSum, err := a.Hash(sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bSum, err := b.Hash(sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(aSum, bSum) {
+		t.Errorf("Hashes differ: %x != %x", aSum, bSum)
+	}
+
+	differing := []string{
+		`{"a":2,"b":[1.5,"two",null,true],"c":{"d":{}}}`,
+		`{"a":1,"b":[1.5,"two",null,false],"c":{"d":{}}}`,
+		`{"a":1,"b":["two",1.5,null,true],"c":{"d":{}}}`,
+		`{"a":1,"b":[1.5,"two",null,true],"c":{"d":[]}}`,
+		`{"a":1,"b":[1.5,"two",null,true],"c":{"d":{}},"e":null}`,
+		`{"a":"1","b":[1.5,"two",null,true],"c":{"d":{}}}`,
+	}
+	for i, d := range differing {
+		c, err := ParseJSON([]byte(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cSum, err := c.Hash(sha256.New)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(aSum, cSum) {
+			t.Errorf("[%d] Expected hash to differ", i)
+		}
+	}
+}
+
+func TestHashUseNumber(t *testing.T) {
+	for i, doc := range []string{`{"a":0.1}`, `[1.1,2.2,1e-7]`, `{"a":{"b":[3.14159,1]}}`} {
+		a, err := ParseJSON([]byte(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseJSONWithOpts([]byte(doc), ParseOptUseNumber())
+		if err != nil {
+			t.Fatal(err)
+		}
+		aSum, err := a.Hash(sha256.New)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bSum, err := b.Hash(sha256.New)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(aSum, bSum) {
+			t.Errorf("[%d] Hashes differ: %x != %x", i, aSum, bSum)
+		}
+	}
+}
+
+func TestHashTypedValues(t *testing.T) {
+	type foo struct {
+		Bar string `json:"bar"`
+	}
+	a, err := Wrap(map[string]interface{}{"foo": foo{Bar: "baz"}}).Hash(sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Wrap(map[string]interface{}{"foo": map[string]interface{}{"bar": "baz"}}).Hash(sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Errorf("Hashes differ: %x != %x", a, b)
+	}
+
+	if _, err = Wrap(map[string]interface{}{"foo": make(chan int)}).Hash(sha256.New); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestHashTree(t *testing.T) {
+	a, err := ParseJSON([]byte(`{"a":{"b":1,"c":[1,2,3]},"d":"same","e":{"f":1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseJSON([]byte(`{"a":{"b":2,"c":[1,2,4]},"d":"same","e":[1],"g":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aTree, err := a.HashTree(sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bTree, err := b.HashTree(sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aSum, err := a.Hash(sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(aSum, aTree.Sum) {
+		t.Errorf("Root of tree does not match hash: %x != %x", aTree.Sum, aSum)
+	}
+	if !bytes.Equal(aTree.Children["d"].Sum, bTree.Children["d"].Sum) {
+		t.Error("Expected matching subtrees to have equal sums")
+	}
+
+	exp := `[["a","b"],["a","c","2"],["e"],["g"]]`
+	if act := Wrap(DiffHashTrees(aTree, bTree)).String(); exp != act {
+		t.Errorf("Wrong diff: %v != %v", act, exp)
+	}
+	if act := Wrap(DiffHashTrees(aTree, aTree)).String(); act != "null" {
+		t.Errorf("Expected no diff, got: %v", act)
+	}
+}
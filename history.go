@@ -0,0 +1,321 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"errors"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+
+var (
+	// ErrNothingToUndo is returned when Undo is called on a History without
+	// any applied changes.
+	ErrNothingToUndo = errors.New("no changes to undo")
+
+	// ErrNothingToRedo is returned when Redo is called on a History without
+	// any undone changes.
+	ErrNothingToRedo = errors.New("no changes to redo")
+)
+
+// ChangeOp identifies the mutation that produced a Change.
+type ChangeOp string
+
+// ChangeOp variants recorded by a History.
+const (
+	ChangeSet         ChangeOp = "set"
+	ChangeDelete      ChangeOp = "delete"
+	ChangeArrayAppend ChangeOp = "array_append"
+	ChangeArrayRemove ChangeOp = "array_remove"
+	ChangeMerge       ChangeOp = "merge"
+)
+
+// Change describes a single mutation recorded by a History.
+type Change struct {
+	// Time is the moment the mutation was first applied.
+	Time time.Time
+
+	// Op is the type of mutation.
+	Op ChangeOp
+
+	// Path is the hierarchy given to the mutating method.
+	Path []string
+
+	// targets are the hierarchies that are restored in order to undo or redo
+	// the change, which can be shallower than Path when the mutation created
+	// new structure or modified an array.
+	targets []changeTarget
+}
+
+// changeTarget records the value at a hierarchy before and after a change.
+type changeTarget struct {
+	path []string
+
+	existedBefore bool
+	before        interface{}
+	existedAfter  bool
+	after         interface{}
+}
+
+//------------------------------------------------------------------------------
+
+// History wraps a Container and records every mutation made through it as an
+// inverse operation, allowing changes to be undone and redone.
+//
+// Only mutations made through the methods of History are recorded, changes
+// made directly to the underlying Container will corrupt the history.
+type History struct {
+	root *Container
+	undo []Change
+	redo []Change
+}
+
+// NewHistory creates a History that tracks mutations of a Container. If root
+// is nil a new empty object is created.
+func NewHistory(root *Container) *History {
+	if root == nil {
+		root = New()
+	}
+	return &History{root: root}
+}
+
+// Container returns the document tracked by the History.
+func (h *History) Container() *Container {
+	return h.root
+}
+
+// Changes returns the list of applied changes in the order they were made.
+// Undone changes are not included until they are redone.
+func (h *History) Changes() []Change {
+	changes := make([]Change, len(h.undo))
+	for i, c := range h.undo {
+		c.Path = append([]string(nil), c.Path...)
+		changes[i] = c
+	}
+	return changes
+}
+
+// CanUndo returns true if there is at least one change that can be undone.
+func (h *History) CanUndo() bool {
+	return len(h.undo) > 0
+}
+
+// CanRedo returns true if there is at least one undone change that can be
+// redone.
+func (h *History) CanRedo() bool {
+	return len(h.redo) > 0
+}
+
+// Undo reverts the most recently applied change.
+func (h *History) Undo() error {
+	if len(h.undo) == 0 {
+		return ErrNothingToUndo
+	}
+	c := h.undo[len(h.undo)-1]
+	for i := len(c.targets) - 1; i >= 0; i-- {
+		t := c.targets[i]
+		if err := h.restore(t.path, t.existedBefore, t.before); err != nil {
+			return err
+		}
+	}
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, c)
+	return nil
+}
+
+// Redo reapplies the most recently undone change.
+func (h *History) Redo() error {
+	if len(h.redo) == 0 {
+		return ErrNothingToRedo
+	}
+	c := h.redo[len(h.redo)-1]
+	for _, t := range c.targets {
+		if err := h.restore(t.path, t.existedAfter, t.after); err != nil {
+			return err
+		}
+	}
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, c)
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// Set works exactly like Container.Set and records the change.
+func (h *History) Set(value interface{}, hierarchy ...string) (*Container, error) {
+	var res *Container
+	err := h.record(ChangeSet, hierarchy, [][]string{h.setTarget(hierarchy)}, func() (err error) {
+		res, err = h.root.Set(value, hierarchy...)
+		return
+	})
+	return res, err
+}
+
+// SetP works exactly like Container.SetP and records the change.
+func (h *History) SetP(value interface{}, path string) (*Container, error) {
+	return h.Set(value, DotPathToSlice(path)...)
+}
+
+// Delete works exactly like Container.Delete and records the change.
+func (h *History) Delete(hierarchy ...string) error {
+	target := hierarchy
+	if len(hierarchy) > 1 {
+		parent := hierarchy[:len(hierarchy)-1]
+		if _, isArray := h.root.Search(parent...).Data().([]interface{}); isArray {
+			target = parent
+		}
+	}
+	return h.record(ChangeDelete, hierarchy, [][]string{target}, func() error {
+		return h.root.Delete(hierarchy...)
+	})
+}
+
+// DeleteP works exactly like Container.DeleteP and records the change.
+func (h *History) DeleteP(path string) error {
+	return h.Delete(DotPathToSlice(path)...)
+}
+
+// ArrayAppend works exactly like Container.ArrayAppend and records the change.
+func (h *History) ArrayAppend(value interface{}, hierarchy ...string) error {
+	return h.record(ChangeArrayAppend, hierarchy, [][]string{h.setTarget(hierarchy)}, func() error {
+		return h.root.ArrayAppend(value, hierarchy...)
+	})
+}
+
+// ArrayAppendP works exactly like Container.ArrayAppendP and records the
+// change.
+func (h *History) ArrayAppendP(value interface{}, path string) error {
+	return h.ArrayAppend(value, DotPathToSlice(path)...)
+}
+
+// ArrayRemove works exactly like Container.ArrayRemove and records the change.
+func (h *History) ArrayRemove(index int, hierarchy ...string) error {
+	return h.record(ChangeArrayRemove, hierarchy, [][]string{hierarchy}, func() error {
+		return h.root.ArrayRemove(index, hierarchy...)
+	})
+}
+
+// ArrayRemoveP works exactly like Container.ArrayRemoveP and records the
+// change.
+func (h *History) ArrayRemoveP(index int, path string) error {
+	return h.ArrayRemove(index, DotPathToSlice(path)...)
+}
+
+// Merge works exactly like Container.Merge and records the change. Only the
+// fields of the document that share a key with the source object are recorded,
+// unless the document is not an object, in which case the whole document is
+// recorded.
+func (h *History) Merge(source *Container) error {
+	srcObj, srcIsObj := source.Data().(map[string]interface{})
+	if !srcIsObj {
+		return h.root.Merge(source)
+	}
+	targets := [][]string{nil}
+	if _, isObj := h.root.Data().(map[string]interface{}); isObj {
+		targets = make([][]string, 0, len(srcObj))
+		for k := range srcObj {
+			targets = append(targets, []string{k})
+		}
+	}
+	return h.record(ChangeMerge, nil, targets, func() error {
+		return h.root.Merge(source)
+	})
+}
+
+//------------------------------------------------------------------------------
+
+// setTarget returns the shallowest hierarchy that captures everything a call
+// to Set with the provided hierarchy could modify. This is either the first
+// segment that does not yet exist, the array being appended to, or the full
+// hierarchy.
+func (h *History) setTarget(hierarchy []string) []string {
+	for i, seg := range hierarchy {
+		if seg == "-" {
+			return hierarchy[:i]
+		}
+		if _, err := h.root.searchStrict(false, hierarchy[:i+1]...); err != nil {
+			return hierarchy[:i+1]
+		}
+	}
+	return hierarchy
+}
+
+func (h *History) snapshot(target []string) (interface{}, bool) {
+	c, err := h.root.searchStrict(false, target...)
+	if err != nil {
+		return nil, false
+	}
+	return deepCopy(c.Data()), true
+}
+
+func (h *History) record(op ChangeOp, path []string, targets [][]string, apply func() error) error {
+	recorded := make([]changeTarget, len(targets))
+	for i, target := range targets {
+		recorded[i].path = append([]string(nil), target...)
+		recorded[i].before, recorded[i].existedBefore = h.snapshot(target)
+	}
+	if err := apply(); err != nil {
+		return err
+	}
+	for i := range recorded {
+		recorded[i].after, recorded[i].existedAfter = h.snapshot(recorded[i].path)
+	}
+	h.undo = append(h.undo, Change{
+		Time:    time.Now(),
+		Op:      op,
+		Path:    append([]string(nil), path...),
+		targets: recorded,
+	})
+	h.redo = nil
+	return nil
+}
+
+func (h *History) restore(target []string, exists bool, value interface{}) error {
+	if !exists {
+		return h.root.Delete(target...)
+	}
+	_, err := h.root.Set(deepCopy(value), target...)
+	return err
+}
+
+// deepCopy returns a copy of a value where all objects and arrays are
+// duplicated, such that the copy can be mutated without modifying the
+// original.
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[k] = deepCopy(e)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(t))
+		for i, e := range t {
+			a[i] = deepCopy(e)
+		}
+		return a
+	}
+	return v
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,216 @@
+package gabs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHistoryUndoRedo(t *testing.T) {
+	root, err := ParseJSON([]byte(`{"foo":{"bar":1},"arr":[1,2,3]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHistory(root)
+
+	if _, err = h.SetP(2, "foo.bar"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = h.SetP("new", "baz.qux.quz"); err != nil {
+		t.Fatal(err)
+	}
+	if err = h.DeleteP("foo.bar"); err != nil {
+		t.Fatal(err)
+	}
+	if err = h.ArrayAppendP(4, "arr"); err != nil {
+		t.Fatal(err)
+	}
+	if err = h.ArrayRemoveP(0, "arr"); err != nil {
+		t.Fatal(err)
+	}
+	if err = h.DeleteP("arr.1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = h.Set(5, "arr", "-"); err != nil {
+		t.Fatal(err)
+	}
+
+	states := []string{
+		`{"arr":[1,2,3],"foo":{"bar":1}}`,
+		`{"arr":[1,2,3],"foo":{"bar":2}}`,
+		`{"arr":[1,2,3],"baz":{"qux":{"quz":"new"}},"foo":{"bar":2}}`,
+		`{"arr":[1,2,3],"baz":{"qux":{"quz":"new"}},"foo":{}}`,
+		`{"arr":[1,2,3,4],"baz":{"qux":{"quz":"new"}},"foo":{}}`,
+		`{"arr":[2,3,4],"baz":{"qux":{"quz":"new"}},"foo":{}}`,
+		`{"arr":[2,4],"baz":{"qux":{"quz":"new"}},"foo":{}}`,
+		`{"arr":[2,4,5],"baz":{"qux":{"quz":"new"}},"foo":{}}`,
+	}
+
+	if exp, act := states[len(states)-1], root.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := len(states)-1, len(h.Changes()); exp != act {
+		t.Errorf("Wrong count of changes: %v != %v", act, exp)
+	}
+
+	for i := len(states) - 2; i >= 0; i-- {
+		if err = h.Undo(); err != nil {
+			t.Fatal(err)
+		}
+		if exp, act := states[i], root.String(); exp != act {
+			t.Errorf("Wrong result after undo %v: %v != %v", i, act, exp)
+		}
+	}
+	if err = h.Undo(); err != ErrNothingToUndo {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for i := 1; i < len(states); i++ {
+		if err = h.Redo(); err != nil {
+			t.Fatal(err)
+		}
+		if exp, act := states[i], root.String(); exp != act {
+			t.Errorf("Wrong result after redo %v: %v != %v", i, act, exp)
+		}
+	}
+	if err = h.Redo(); err != ErrNothingToRedo {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestHistoryRedoClearedByMutation(t *testing.T) {
+	h := NewHistory(nil)
+	if _, err := h.SetP(1, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if !h.CanRedo() {
+		t.Error("Expected redo to be available")
+	}
+	if _, err := h.SetP(2, "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if h.CanRedo() {
+		t.Error("Expected redo to be cleared")
+	}
+	if exp, act := `{"bar":2}`, h.Container().String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func TestHistoryFailedMutation(t *testing.T) {
+	h := NewHistory(Wrap(map[string]interface{}{"foo": "bar"}))
+	if _, err := h.SetP(1, "foo.bar"); err == nil {
+		t.Error("Expected error")
+	}
+	if err := h.DeleteP("nope"); err == nil {
+		t.Error("Expected error")
+	}
+	if h.CanUndo() {
+		t.Error("Failed mutations should not be recorded")
+	}
+}
+
+func TestHistoryMerge(t *testing.T) {
+	h := NewHistory(Wrap(map[string]interface{}{
+		"foo":       map[string]interface{}{"bar": "baz"},
+		"untouched": map[string]interface{}{"big": "value"},
+	}))
+	source, err := ParseJSON([]byte(`{"foo":{"bar":"qux","quz":1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = h.Merge(source); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := [][]string{{"foo"}}, changeTargetPaths(h.undo[0]); !reflect.DeepEqual(exp, act) {
+		t.Errorf("Wrong recorded targets: %v != %v", act, exp)
+	}
+	merged := `{"foo":{"bar":["baz","qux"],"quz":1},"untouched":{"big":"value"}}`
+	if exp, act := merged, h.Container().String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if err = h.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"foo":{"bar":"baz"},"untouched":{"big":"value"}}`, h.Container().String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if err = h.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := merged, h.Container().String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func TestHistoryMergeNonObject(t *testing.T) {
+	h := NewHistory(Wrap([]interface{}{"foo"}))
+	source, err := ParseJSON([]byte(`{"bar":"baz"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = h.Merge(source); err == nil {
+		t.Fatal("Expected error merging an object into an array")
+	}
+	if len(h.Changes()) != 0 {
+		t.Error("Failed merges should not be recorded")
+	}
+
+	h = NewHistory(Wrap(nil))
+	if err = h.Merge(source); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := [][]string{nil}, changeTargetPaths(h.undo[0]); !reflect.DeepEqual(exp, act) {
+		t.Errorf("Wrong recorded targets: %v != %v", act, exp)
+	}
+	if err = h.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `null`, h.Container().String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func changeTargetPaths(c Change) [][]string {
+	paths := make([][]string, len(c.targets))
+	for i, t := range c.targets {
+		paths[i] = t.path
+	}
+	return paths
+}
+
+func TestHistoryChanges(t *testing.T) {
+	h := NewHistory(nil)
+	if _, err := h.SetP(1, "foo.bar"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.ArrayAppend("a", "baz"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Delete("foo", "bar"); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := h.Changes()
+	if exp, act := 3, len(changes); exp != act {
+		t.Fatalf("Wrong count of changes: %v != %v", act, exp)
+	}
+	expOps := []ChangeOp{ChangeSet, ChangeArrayAppend, ChangeDelete}
+	expPaths := []string{`["foo","bar"]`, `["baz"]`, `["foo","bar"]`}
+	for i, c := range changes {
+		if exp, act := expOps[i], c.Op; exp != act {
+			t.Errorf("Wrong op %v: %v != %v", i, act, exp)
+		}
+		if exp, act := expPaths[i], Wrap(c.Path).String(); exp != act {
+			t.Errorf("Wrong path %v: %v != %v", i, act, exp)
+		}
+		if c.Time.IsZero() {
+			t.Errorf("Missing time for change %v", i)
+		}
+		if i > 0 && c.Time.Before(changes[i-1].Time) {
+			t.Errorf("Change %v is out of order", i)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"sort"
+)
+
+//------------------------------------------------------------------------------
+
+// InferSchema derives a JSON Schema (draft 2020-12) that describes the
+// structure observed across a set of sample documents, intended as a starting
+// point for documenting them.
+//
+// The schema of each value lists every type observed at its path, where
+// numbers are integers when every sample is an integer. Objects list all
+// observed properties, with those present in every sample being required.
+// Arrays describe the merged schema of all of their items. Numbers are given
+// the range of the samples as their minimum and maximum, strings are given a
+// format when every sample is a date-time, date, uuid, email, ipv4 or uri, and
+// otherwise values that are only ever strings or null, where the strings are
+// repeated across samples with few distinct values, are given an enum.
+func InferSchema(samples ...*Container) *Container {
+	root := &sampleShape{}
+	for _, s := range samples {
+		root.merge(s.Data())
+	}
+	schema := root.schema()
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	return &Container{object: schema}
+}
+
+func (s *sampleShape) schema() map[string]interface{} {
+	schema := map[string]interface{}{}
+
+	var types []interface{}
+	for _, t := range []struct {
+		kind shapeKind
+		name string
+	}{
+		{shapeKindNull, "null"},
+		{shapeKindBool, "boolean"},
+		{shapeKindInt, "integer"},
+		{shapeKindFloat, "number"},
+		{shapeKindString, "string"},
+		{shapeKindObject, "object"},
+		{shapeKindArray, "array"},
+	} {
+		if s.kinds&t.kind == 0 || (t.kind == shapeKindInt && s.kinds&shapeKindFloat != 0) {
+			continue
+		}
+		types = append(types, t.name)
+	}
+	switch len(types) {
+	case 0:
+	case 1:
+		schema["type"] = types[0]
+	default:
+		schema["type"] = types
+	}
+
+	if s.minRat != nil {
+		schema["minimum"], schema["maximum"] = s.min, s.max
+	}
+
+	if s.kinds&shapeKindString != 0 {
+		if format := s.formatName(); format != "" {
+			schema["format"] = format
+		} else if s.kinds&^shapeKindNull == shapeKindString && len(s.values) > 0 && s.strings >= 2*len(s.values) {
+			enum := make([]string, 0, len(s.values))
+			for v := range s.values {
+				enum = append(enum, v)
+			}
+			sort.Strings(enum)
+			values := make([]interface{}, 0, len(enum)+1)
+			for _, v := range enum {
+				values = append(values, v)
+			}
+			if s.kinds&shapeKindNull != 0 {
+				values = append(values, nil)
+			}
+			schema["enum"] = values
+		}
+	}
+
+	if len(s.fields) > 0 {
+		properties := make(map[string]interface{}, len(s.fields))
+		var required []string
+		for k, f := range s.fields {
+			properties[k] = f.schema()
+			if f.present == s.objects {
+				required = append(required, k)
+			}
+		}
+		schema["properties"] = properties
+		if len(required) > 0 {
+			sort.Strings(required)
+			requiredValues := make([]interface{}, len(required))
+			for i, k := range required {
+				requiredValues[i] = k
+			}
+			schema["required"] = requiredValues
+		}
+	}
+
+	if s.elem != nil && s.elem.kinds != 0 {
+		schema["items"] = s.elem.schema()
+	}
+	return schema
+}
+
+// formatName returns the name of the highest priority format satisfied by all
+// strings of the shape, or an empty string if there is none.
+func (s *sampleShape) formatName() string {
+	for _, f := range stringFormatNames {
+		if s.formats&f.format != 0 {
+			return f.name
+		}
+	}
+	return ""
+}
+
+//------------------------------------------------------------------------------
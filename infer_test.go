@@ -0,0 +1,74 @@
+package gabs
+
+import (
+	"testing"
+)
+
+func TestInferSchema(t *testing.T) {
+	type testCase struct {
+		samples []string
+		output  string
+	}
+	tests := []testCase{
+		{
+			samples: []string{`{"id":1,"name":"foo","score":1.5}`, `{"id":7,"score":-2,"extra":null}`},
+			output:  `{"$schema":"https://json-schema.org/draft/2020-12/schema","properties":{"extra":{"type":"null"},"id":{"maximum":7,"minimum":1,"type":"integer"},"name":{"type":"string"},"score":{"maximum":1.5,"minimum":-2,"type":"number"}},"required":["id","score"],"type":"object"}`,
+		},
+		{
+			samples: []string{`{"status":"ok"}`, `{"status":"ok"}`, `{"status":"ok"}`, `{"status":"err"}`, `{"status":null}`},
+			output:  `{"$schema":"https://json-schema.org/draft/2020-12/schema","properties":{"status":{"enum":["err","ok",null],"type":["null","string"]}},"required":["status"],"type":"object"}`,
+		},
+		{
+			samples: []string{`{"name":"a"}`, `{"name":"b"}`, `{"name":"a"}`},
+			output:  `{"$schema":"https://json-schema.org/draft/2020-12/schema","properties":{"name":{"type":"string"}},"required":["name"],"type":"object"}`,
+		},
+		{
+			samples: []string{`{"at":"2023-01-02T15:04:05Z","day":"2023-01-02","id":"123e4567-e89b-12d3-a456-426614174000","mail":"a@b.com","ip":"10.0.0.1","link":"https://example.com/x"}`},
+			output:  `{"$schema":"https://json-schema.org/draft/2020-12/schema","properties":{"at":{"format":"date-time","type":"string"},"day":{"format":"date","type":"string"},"id":{"format":"uuid","type":"string"},"ip":{"format":"ipv4","type":"string"},"link":{"format":"uri","type":"string"},"mail":{"format":"email","type":"string"}},"required":["at","day","id","ip","link","mail"],"type":"object"}`,
+		},
+		{
+			samples: []string{`{"at":"2023-01-02T15:04:05Z"}`, `{"at":"yesterday"}`},
+			output:  `{"$schema":"https://json-schema.org/draft/2020-12/schema","properties":{"at":{"type":"string"}},"required":["at"],"type":"object"}`,
+		},
+		{
+			samples: []string{`[{"a":1},{"a":2,"b":true}]`, `[]`},
+			output:  `{"$schema":"https://json-schema.org/draft/2020-12/schema","items":{"properties":{"a":{"maximum":2,"minimum":1,"type":"integer"},"b":{"type":"boolean"}},"required":["a"],"type":"object"},"type":"array"}`,
+		},
+		{
+			samples: []string{`{"v":[]}`, `{"v":"x"}`, `{"v":1}`},
+			output:  `{"$schema":"https://json-schema.org/draft/2020-12/schema","properties":{"v":{"maximum":1,"minimum":1,"type":["integer","string","array"]}},"required":["v"],"type":"object"}`,
+		},
+		{
+			samples: nil,
+			output:  `{"$schema":"https://json-schema.org/draft/2020-12/schema"}`,
+		},
+	}
+
+	for i, test := range tests {
+		var samples []*Container
+		for _, s := range test.samples {
+			c, err := ParseJSON([]byte(s))
+			if err != nil {
+				t.Fatal(err)
+			}
+			samples = append(samples, c)
+		}
+		if exp, act := test.output, InferSchema(samples...).String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestInferSchemaUseNumber(t *testing.T) {
+	a, err := ParseJSONWithOpts([]byte(`{"n":12345678901234567890}`), ParseOptUseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseJSONWithOpts([]byte(`{"n":1.10}`), ParseOptUseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"maximum":12345678901234567890,"minimum":1.10,"type":"number"}`, InferSchema(a, b).Path("properties.n").String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
@@ -0,0 +1,491 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+//------------------------------------------------------------------------------
+
+// ParseJSON5 parses a JSON5 document into a *Container. JSON5 extends JSON
+// with comments, trailing commas, single quoted strings, unquoted object keys,
+// hexadecimal numbers, leading and trailing decimal points, explicit plus
+// signs and the values Infinity and NaN. Since JSONC (JSON with comments) is a
+// subset of JSON5 it is also accepted.
+//
+// Numbers are parsed as float64 values, just as ParseJSON. Note that the
+// values Infinity and NaN cannot be serialized back into standard JSON.
+func ParseJSON5(sample []byte) (*Container, error) {
+	p := json5Parser{src: sample}
+	if bytes.HasPrefix(p.src, []byte("\uFEFF")) {
+		p.pos = 3
+	}
+	if err := p.skipSpace(); err != nil {
+		return nil, err
+	}
+	v, err := p.parseValue(0)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.skipSpace(); err != nil {
+		return nil, err
+	}
+	if !p.eof() {
+		return nil, p.errorf("unexpected character %q after top level value", p.peekRune())
+	}
+	return &Container{object: v}, nil
+}
+
+// ParseJSON5File reads a file and unmarshals the contents as JSON5 into a
+// *Container.
+func ParseJSON5File(path string) (*Container, error) {
+	if path == "" {
+		return nil, ErrInvalidPath
+	}
+	cBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJSON5(cBytes)
+}
+
+//------------------------------------------------------------------------------
+
+const json5MaxDepth = 10000
+
+type json5Parser struct {
+	src []byte
+	pos int
+}
+
+func (p *json5Parser) errorf(format string, args ...interface{}) error {
+	line := bytes.Count(p.src[:p.pos], []byte("\n")) + 1
+	return fmt.Errorf("json5: line %v: %v", line, fmt.Sprintf(format, args...))
+}
+
+func (p *json5Parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *json5Parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *json5Parser) peekRune() rune {
+	r, _ := utf8.DecodeRune(p.src[p.pos:])
+	return r
+}
+
+func (p *json5Parser) hasPrefix(s string) bool {
+	return bytes.HasPrefix(p.src[p.pos:], []byte(s))
+}
+
+func isJSON5Space(r rune) bool {
+	return r == '\uFEFF' || unicode.IsSpace(r) || unicode.Is(unicode.Zs, r)
+}
+
+func isJSON5LineTerminator(r rune) bool {
+	return r == '\n' || r == '\r' || r == '\u2028' || r == '\u2029'
+}
+
+// skipSpace consumes whitespace and comments.
+func (p *json5Parser) skipSpace() error {
+	for !p.eof() {
+		switch {
+		case p.hasPrefix("//"):
+			for !p.eof() && !isJSON5LineTerminator(p.peekRune()) {
+				_, size := utf8.DecodeRune(p.src[p.pos:])
+				p.pos += size
+			}
+		case p.hasPrefix("/*"):
+			end := bytes.Index(p.src[p.pos+2:], []byte("*/"))
+			if end < 0 {
+				return p.errorf("unterminated block comment")
+			}
+			p.pos += end + 4
+		default:
+			r, size := utf8.DecodeRune(p.src[p.pos:])
+			if !isJSON5Space(r) {
+				return nil
+			}
+			p.pos += size
+		}
+	}
+	return nil
+}
+
+func (p *json5Parser) parseValue(depth int) (interface{}, error) {
+	if depth > json5MaxDepth {
+		return nil, p.errorf("exceeded maximum nesting depth")
+	}
+	if p.eof() {
+		return nil, p.errorf("unexpected end of input")
+	}
+	switch c := p.peek(); {
+	case c == '{':
+		return p.parseObject(depth)
+	case c == '[':
+		return p.parseArray(depth)
+	case c == '"' || c == '\'':
+		return p.parseString()
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	}
+	for _, lit := range []struct {
+		text  string
+		value interface{}
+	}{
+		{"true", true},
+		{"false", false},
+		{"null", nil},
+		{"Infinity", math.Inf(1)},
+		{"NaN", math.NaN()},
+	} {
+		if p.hasPrefix(lit.text) {
+			p.pos += len(lit.text)
+			if !p.eof() && isJSON5IdentifierPart(p.peekRune()) {
+				p.pos -= len(lit.text)
+				break
+			}
+			return lit.value, nil
+		}
+	}
+	return nil, p.errorf("unexpected character %q", p.peekRune())
+}
+
+func (p *json5Parser) parseObject(depth int) (interface{}, error) {
+	p.pos++
+	obj := map[string]interface{}{}
+	for {
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return obj, nil
+		}
+
+		var key string
+		var err error
+		if c := p.peek(); c == '"' || c == '\'' {
+			key, err = p.parseString()
+		} else {
+			key, err = p.parseIdentifier()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err = p.skipSpace(); err != nil {
+			return nil, err
+		}
+		if p.peek() != ':' {
+			return nil, p.errorf("expected ':' after object key '%v'", key)
+		}
+		p.pos++
+		if err = p.skipSpace(); err != nil {
+			return nil, err
+		}
+		if obj[key], err = p.parseValue(depth + 1); err != nil {
+			return nil, err
+		}
+
+		if err = p.skipSpace(); err != nil {
+			return nil, err
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return obj, nil
+		default:
+			if p.eof() {
+				return nil, p.errorf("unexpected end of input, object is not closed")
+			}
+			return nil, p.errorf("expected ',' or '}' within object, found %q", p.peekRune())
+		}
+	}
+}
+
+func (p *json5Parser) parseArray(depth int) (interface{}, error) {
+	p.pos++
+	arr := []interface{}{}
+	for {
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return arr, nil
+		}
+
+		v, err := p.parseValue(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+
+		if err = p.skipSpace(); err != nil {
+			return nil, err
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return arr, nil
+		default:
+			if p.eof() {
+				return nil, p.errorf("unexpected end of input, array is not closed")
+			}
+			return nil, p.errorf("expected ',' or ']' within array, found %q", p.peekRune())
+		}
+	}
+}
+
+func isJSON5IdentifierStart(r rune) bool {
+	return r == '$' || r == '_' || unicode.IsLetter(r) || unicode.Is(unicode.Nl, r)
+}
+
+func isJSON5IdentifierPart(r rune) bool {
+	return isJSON5IdentifierStart(r) ||
+		unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc) ||
+		r == '\u200C' || r == '\u200D'
+}
+
+func (p *json5Parser) parseIdentifier() (string, error) {
+	var sb strings.Builder
+	for !p.eof() {
+		start := p.pos
+		r, size := utf8.DecodeRune(p.src[p.pos:])
+		p.pos += size
+		if r == '\\' {
+			if p.peek() != 'u' {
+				return "", p.errorf("invalid escape sequence in object key")
+			}
+			p.pos++
+			var err error
+			if r, err = p.parseHex(4); err != nil {
+				return "", err
+			}
+		}
+		valid := isJSON5IdentifierPart(r)
+		if sb.Len() == 0 {
+			valid = isJSON5IdentifierStart(r)
+		}
+		if !valid {
+			p.pos = start
+			break
+		}
+		sb.WriteRune(r)
+	}
+	if sb.Len() == 0 {
+		if p.eof() {
+			return "", p.errorf("unexpected end of input, expected an object key")
+		}
+		return "", p.errorf("unexpected character %q, expected an object key", p.peekRune())
+	}
+	return sb.String(), nil
+}
+
+func (p *json5Parser) parseHex(n int) (rune, error) {
+	if len(p.src)-p.pos < n {
+		return 0, p.errorf("unexpected end of input within escape sequence")
+	}
+	v, err := strconv.ParseUint(string(p.src[p.pos:p.pos+n]), 16, 32)
+	if err != nil {
+		return 0, p.errorf("invalid hexadecimal escape sequence")
+	}
+	p.pos += n
+	return rune(v), nil
+}
+
+func (p *json5Parser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unexpected end of input, string is not terminated")
+		}
+		r, size := utf8.DecodeRune(p.src[p.pos:])
+		p.pos += size
+		switch {
+		case r == rune(quote):
+			return sb.String(), nil
+		case r == '\n' || r == '\r':
+			return "", p.errorf("unescaped line terminator within string")
+		case r != '\\':
+			sb.WriteRune(r)
+			continue
+		}
+
+		if p.eof() {
+			return "", p.errorf("unexpected end of input, string is not terminated")
+		}
+		r, size = utf8.DecodeRune(p.src[p.pos:])
+		p.pos += size
+		switch r {
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'v':
+			sb.WriteByte('\v')
+		case '0':
+			if c := p.peek(); c >= '0' && c <= '9' {
+				return "", p.errorf("invalid escape sequence '\\0%c'", c)
+			}
+			sb.WriteByte(0)
+		case 'x':
+			h, err := p.parseHex(2)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteRune(h)
+		case 'u':
+			h, err := p.parseHex(4)
+			if err != nil {
+				return "", err
+			}
+			if utf16.IsSurrogate(h) && p.hasPrefix(`\u`) {
+				p.pos += 2
+				low, err := p.parseHex(4)
+				if err != nil {
+					return "", err
+				}
+				h = utf16.DecodeRune(h, low)
+			}
+			sb.WriteRune(h)
+		case '\r':
+			if p.peek() == '\n' {
+				p.pos++
+			}
+		case '\n', '\u2028', '\u2029':
+		default:
+			if r >= '1' && r <= '9' {
+				return "", p.errorf("invalid escape sequence '\\%c'", r)
+			}
+			sb.WriteRune(r)
+		}
+	}
+}
+
+func (p *json5Parser) parseNumber() (interface{}, error) {
+	start := p.pos
+	sign := 1.0
+	if c := p.peek(); c == '+' || c == '-' {
+		if c == '-' {
+			sign = -1
+		}
+		p.pos++
+	}
+
+	switch {
+	case p.hasPrefix("Infinity"):
+		p.pos += len("Infinity")
+		return math.Inf(int(sign)), nil
+	case p.hasPrefix("NaN"):
+		p.pos += len("NaN")
+		return math.NaN(), nil
+	case p.hasPrefix("0x") || p.hasPrefix("0X"):
+		p.pos += 2
+		digitsStart := p.pos
+		for !p.eof() && strings.IndexByte("0123456789abcdefABCDEF", p.peek()) >= 0 {
+			p.pos++
+		}
+		if p.pos == digitsStart {
+			return nil, p.errorf("invalid hexadecimal number")
+		}
+		v, err := strconv.ParseUint(string(p.src[digitsStart:p.pos]), 16, 64)
+		if err != nil {
+			b, _ := new(big.Int).SetString(string(p.src[digitsStart:p.pos]), 16)
+			f, _ := new(big.Float).SetInt(b).Float64()
+			return sign * f, nil
+		}
+		return sign * float64(v), nil
+	}
+
+	digits := func() int {
+		n := 0
+		for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+			p.pos++
+			n++
+		}
+		return n
+	}
+
+	intStart := p.pos
+	intDigits := digits()
+	if intDigits > 1 && p.src[intStart] == '0' {
+		return nil, p.errorf("numbers cannot have leading zeros")
+	}
+	fracDigits := 0
+	if p.peek() == '.' {
+		p.pos++
+		fracDigits = digits()
+	}
+	if intDigits == 0 && fracDigits == 0 {
+		return nil, p.errorf("invalid number")
+	}
+	if c := p.peek(); c == 'e' || c == 'E' {
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		if digits() == 0 {
+			return nil, p.errorf("invalid number exponent")
+		}
+	}
+	if !p.eof() && isJSON5IdentifierPart(p.peekRune()) {
+		return nil, p.errorf("unexpected character %q within number", p.peekRune())
+	}
+
+	f, err := strconv.ParseFloat(string(p.src[start:p.pos]), 64)
+	if err != nil {
+		if numErr, ok := err.(*strconv.NumError); !ok || numErr.Err != strconv.ErrRange {
+			return nil, p.errorf("invalid number '%s'", p.src[start:p.pos])
+		}
+	}
+	return f, nil
+}
+
+//------------------------------------------------------------------------------
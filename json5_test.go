@@ -0,0 +1,139 @@
+package gabs
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseJSON5(t *testing.T) {
+	type testCase struct {
+		input  string
+		output string
+	}
+	tests := []testCase{
+		{input: `{"a":1}`, output: `{"a":1}`},
+		{input: `[1,2,3,]`, output: `[1,2,3]`},
+		{input: `{a:1,b:[true,false,null,],}`, output: `{"a":1,"b":[true,false,null]}`},
+		{input: `{$_x1:1,ünï:2,ab:3}`, output: `{"$_x1":1,"ab":3,"ünï":2}`},
+		{input: `'single "quoted"'`, output: `"single \"quoted\""`},
+		{input: `"double 'quoted'"`, output: `"double 'quoted'"`},
+		{input: `'\x41é\t\v\0\'\/\q'`, output: `"Aé\t\u000b\u0000'/q"`},
+		{input: `'😀'`, output: `"😀"`},
+		{input: "'line \\\ncontinued'", output: `"line continued"`},
+		{input: `0x1F`, output: `31`},
+		{input: `-0XfF`, output: `-255`},
+		{input: `+5`, output: `5`},
+		{input: `.5`, output: `0.5`},
+		{input: `5.`, output: `5`},
+		{input: `1e3`, output: `1000`},
+		{input: `-1.5E-1`, output: `-0.15`},
+		{input: `0`, output: `0`},
+		{
+			input: `// leading comment
+{
+	/* block
+	   comment */
+	name: 'gabs', // trailing comment
+	// commented: 'out',
+	list: [
+		1, // one
+		2, /* two */
+	],
+}
+/* final */`,
+			output: `{"list":[1,2],"name":"gabs"}`,
+		},
+		{input: "\uFEFF {\u00A0a\u2028:\t1 }", output: `{"a":1}`},
+	}
+
+	for i, test := range tests {
+		c, err := ParseJSON5([]byte(test.input))
+		if err != nil {
+			t.Errorf("[%d] Failed to parse: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, c.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestParseJSON5SpecialNumbers(t *testing.T) {
+	c, err := ParseJSON5([]byte(`[Infinity, -Infinity, +Infinity, NaN, -NaN]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := c.Children()
+	if v := values[0].Data().(float64); !math.IsInf(v, 1) {
+		t.Errorf("Wrong result: %v", v)
+	}
+	if v := values[1].Data().(float64); !math.IsInf(v, -1) {
+		t.Errorf("Wrong result: %v", v)
+	}
+	if v := values[2].Data().(float64); !math.IsInf(v, 1) {
+		t.Errorf("Wrong result: %v", v)
+	}
+	if v := values[3].Data().(float64); !math.IsNaN(v) {
+		t.Errorf("Wrong result: %v", v)
+	}
+	if v := values[4].Data().(float64); !math.IsNaN(v) {
+		t.Errorf("Wrong result: %v", v)
+	}
+}
+
+func TestParseJSON5Errors(t *testing.T) {
+	tests := []string{
+		``,
+		`// only a comment`,
+		`{a:1`,
+		`[1,2`,
+		`[1,,2]`,
+		`[,]`,
+		`{,}`,
+		`{a 1}`,
+		`{1a:1}`,
+		`{a-b:1}`,
+		`'unterminated`,
+		"'new\nline'",
+		`'\1'`,
+		`'\01'`,
+		`'\xZZ'`,
+		`01`,
+		`1.e`,
+		`.`,
+		`0x`,
+		`1a`,
+		`Infinityx`,
+		`tru`,
+		`/* open`,
+		`{} {}`,
+		`{a:1} x`,
+	}
+
+	for i, test := range tests {
+		if _, err := ParseJSON5([]byte(test)); err == nil {
+			t.Errorf("[%d] Expected error from '%v'", i, test)
+		}
+	}
+}
+
+func TestParseJSON5File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json5")
+	if err := os.WriteFile(path, []byte("{\n\t// port: 80,\n\tport: 8080,\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := ParseJSON5File(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"port":8080}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	if _, err = ParseJSON5File(""); err != ErrInvalidPath {
+		t.Errorf("Expected ErrInvalidPath, received: %v", err)
+	}
+}
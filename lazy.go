@@ -0,0 +1,169 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+//------------------------------------------------------------------------------
+
+// ParseJSONLazy parses a JSON byte slice into a *Container that retains the
+// raw input, decoding values only when they are accessed. Methods that walk a
+// path, such as Search, Path, Set and Delete, and methods that list children,
+// such as Children and ChildrenMap, decode only the objects and arrays that
+// they pass through, and only one level at a time. Calling Data on a container
+// decodes all of its contents.
+//
+// Values that are never decoded are written back out verbatim (other than
+// whitespace) by Bytes, String and MarshalJSON, which makes this mode well
+// suited to reading or modifying a few fields of a large document.
+//
+// Parse options, such as ParseOptUseNumber, are applied to values as they are
+// decoded. The input is validated when parsed, and is not copied, therefore it
+// must not be modified for the lifetime of the container.
+//
+// Since values are decoded in place as they are accessed, methods that only
+// read from the container, such as Path and Children, may also modify the
+// underlying structure. The container is therefore not safe for concurrent use,
+// including concurrent reads, until Data has been called on it, after which it
+// and any containers obtained from it are safe for concurrent reads.
+func ParseJSONLazy(sample []byte, opts ...ParseOpt) (*Container, error) {
+	var raw json.RawMessage
+	if err := json.Unmarshal(sample, &raw); err != nil {
+		return nil, err
+	}
+	o := newParseOpts(opts)
+	return &Container{object: lazyJSON(bytes.TrimSpace(sample)), lazy: o, parse: o}, nil
+}
+
+//------------------------------------------------------------------------------
+
+// lazyJSON is a valid JSON value that has not yet been decoded.
+type lazyJSON []byte
+
+// MarshalJSON returns the raw value.
+func (l lazyJSON) MarshalJSON() ([]byte, error) {
+	return l, nil
+}
+
+// expandLazy decodes a single level of a lazy value, where the values of an
+// object or array remain lazy.
+func (o *parseOpts) expandLazy(v interface{}) interface{} {
+	raw, ok := v.(lazyJSON)
+	if !ok {
+		return v
+	}
+	switch raw[0] {
+	case '{':
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil
+		}
+		obj := make(map[string]interface{}, len(fields))
+		for k, f := range fields {
+			obj[k] = lazyJSON(f)
+		}
+		return obj
+	case '[':
+		var elements []json.RawMessage
+		if err := json.Unmarshal(raw, &elements); err != nil {
+			return nil
+		}
+		arr := make([]interface{}, len(elements))
+		for i, e := range elements {
+			arr[i] = lazyJSON(e)
+		}
+		return arr
+	}
+	var scalar interface{}
+	if err := o.unmarshal(raw, &scalar); err != nil {
+		return nil
+	}
+	return scalar
+}
+
+// decodeLazy fully decodes a value, replacing lazy values nested within
+// objects and arrays in place. Values that are already decoded are left
+// untouched.
+func (o *parseOpts) decodeLazy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case lazyJSON:
+		var decoded interface{}
+		if err := o.unmarshal(t, &decoded); err != nil {
+			return nil
+		}
+		return decoded
+	case map[string]interface{}:
+		for k, e := range t {
+			if _, isLazy := e.(lazyJSON); isLazy {
+				t[k] = o.decodeLazy(e)
+			} else {
+				o.decodeLazy(e)
+			}
+		}
+	case []interface{}:
+		for i, e := range t {
+			if _, isLazy := e.(lazyJSON); isLazy {
+				t[i] = o.decodeLazy(e)
+			} else {
+				o.decodeLazy(e)
+			}
+		}
+	}
+	return v
+}
+
+// expandField expands a field of an object in place and returns it.
+func (g *Container) expandField(obj map[string]interface{}, key string) interface{} {
+	v := obj[key]
+	if g.lazy != nil {
+		if _, isLazy := v.(lazyJSON); isLazy {
+			v = g.lazy.expandLazy(v)
+			obj[key] = v
+		}
+	}
+	return v
+}
+
+// expandElement expands an element of an array in place and returns it.
+func (g *Container) expandElement(arr []interface{}, index int) interface{} {
+	v := arr[index]
+	if g.lazy != nil {
+		if _, isLazy := v.(lazyJSON); isLazy {
+			v = g.lazy.expandLazy(v)
+			arr[index] = v
+		}
+	}
+	return v
+}
+
+// encodable returns the underlying value for serialization, where values that
+// have not been decoded are serialized from their raw form.
+func (g *Container) encodable() interface{} {
+	if g == nil {
+		return nil
+	}
+	return g.object
+}
+
+//------------------------------------------------------------------------------
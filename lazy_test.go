@@ -0,0 +1,190 @@
+package gabs
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestParseJSONLazy(t *testing.T) {
+	input := `{
+		"id": "abc",
+		"meta": {"type": "push", "tags": ["a", "b"]},
+		"payload": {"big": [1.50, 2e3, {"x": "y"}], "keep":   true}
+	}`
+
+	c, err := ParseJSONLazy([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, act := "push", c.Path("meta.type").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := "b", c.Path("meta.tags.1").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := 2, len(c.Path("meta.tags").Children()); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if !c.ExistsP("payload.big.2.x") {
+		t.Error("Expected path to exist")
+	}
+	if c.ExistsP("payload.big.3") {
+		t.Error("Expected path not to exist")
+	}
+
+	// Untouched values are serialized from their raw form.
+	if exp, act := `{"big":[1.50,2e3,{"x":"y"}],"keep":true}`, c.Path("payload").String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	if _, err = c.SetP("pull", "meta.type"); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.DeleteP("id"); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.ArrayAppendP("c", "meta.tags"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"meta":{"tags":["a","b","c"],"type":"pull"},"payload":{"big":[1.50,2e3,{"x":"y"}],"keep":true}}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	// Data decodes everything.
+	payload, ok := c.Path("payload").Data().(map[string]interface{})
+	if !ok {
+		t.Fatalf("Wrong type: %T", c.Path("payload").Data())
+	}
+	if exp, act := 2000.0, payload["big"].([]interface{})[1]; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := `{"meta":{"tags":["a","b","c"],"type":"pull"},"payload":{"big":[1.5,2000,{"x":"y"}],"keep":true}}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func TestParseJSONLazyChildren(t *testing.T) {
+	c, err := ParseJSONLazy([]byte(`[{"a": 1}, {"a": 2}, [3], 4]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	children := c.Children()
+	if exp, act := 4, len(children); exp != act {
+		t.Fatalf("Wrong count: %v != %v", act, exp)
+	}
+	if _, err = children[0].Set(10, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := 4.0, children[3].Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := 3.0, c.Index(2).Index(0).Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := `[10,2]`, c.Search("*", "a").String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := `[{"a":10},{"a":2},[3],4]`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	m, err := ParseJSONLazy([]byte(`{"x": {"y": [1, 2]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := m.ChildrenMap()["x"].ArrayCount("y"); err != nil || n != 2 {
+		t.Errorf("Wrong count: %v, %v", n, err)
+	}
+	if _, err = m.ChildrenMap()["x"].SetP(true, "z"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"x":{"y":[1,2],"z":true}}`, m.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if !Equal(m, Wrap(map[string]interface{}{"x": map[string]interface{}{"y": []interface{}{1, 2}, "z": true}})) {
+		t.Error("Expected containers to be equal")
+	}
+}
+
+func TestParseJSONLazyNull(t *testing.T) {
+	c, err := ParseJSONLazy([]byte(` null `))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Set(1, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"a":1}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func TestParseJSONLazyConcurrentReads(t *testing.T) {
+	c, err := ParseJSONLazy([]byte(`{"a": {"b": [1, {"c": 2}]}, "d": [3, 4]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Data()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if exp, act := 2.0, c.Path("a.b.1.c").Data(); exp != act {
+				t.Errorf("Wrong result: %v != %v", act, exp)
+			}
+			if exp, act := 2, len(c.Path("d").Children()); exp != act {
+				t.Errorf("Wrong count: %v != %v", act, exp)
+			}
+			if exp, act := `{"a":{"b":[1,{"c":2}]},"d":[3,4]}`, c.String(); exp != act {
+				t.Errorf("Wrong result: %v != %v", act, exp)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestParseJSONLazyErrors(t *testing.T) {
+	for i, test := range []string{``, `{`, `{"a": 1} x`, `[1,]`} {
+		if _, err := ParseJSONLazy([]byte(test)); err == nil {
+			t.Errorf("[%d] Expected error from '%v'", i, test)
+		}
+	}
+}
+
+func BenchmarkParseJSONLazy(b *testing.B) {
+	doc := []byte(`{"id":"abc","meta":{"type":"push"},"payload":[` + benchmarkLazyPayload + `]}`)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c, err := ParseJSONLazy(doc)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if c.Path("meta.type").Data() != "push" {
+			b.Fatal("wrong result")
+		}
+		_ = c.Bytes()
+	}
+}
+
+func BenchmarkParseJSONEager(b *testing.B) {
+	doc := []byte(`{"id":"abc","meta":{"type":"push"},"payload":[` + benchmarkLazyPayload + `]}`)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c, err := ParseJSON(doc)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if c.Path("meta.type").Data() != "push" {
+			b.Fatal("wrong result")
+		}
+		_ = c.Bytes()
+	}
+}
+
+const benchmarkLazyPayload = `{"k":"value","n":[1,2,3],"o":{"a":true,"b":null}},{"k":"value","n":[1,2,3],"o":{"a":true,"b":null}},{"k":"value","n":[1,2,3],"o":{"a":true,"b":null}},{"k":"value","n":[1,2,3],"o":{"a":true,"b":null}}`
@@ -0,0 +1,493 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+
+// MsgPackExt represents a MessagePack extension value of an application
+// specific type. Timestamp extensions (type -1) are decoded as time.Time
+// instead.
+type MsgPackExt struct {
+	Type int8
+	Data []byte
+}
+
+// ParseMsgPack decodes a MessagePack encoded value into a *Container. Maps and
+// arrays are decoded into map[string]interface{} and []interface{} just as
+// ParseJSON would produce, and map keys that are not strings are converted
+// into their JSON representation.
+//
+// Integers are decoded as float64 when they can be represented exactly,
+// otherwise as json.Number. Binary values are decoded as []byte, timestamps as
+// time.Time and all other extension types as MsgPackExt.
+func ParseMsgPack(sample []byte) (*Container, error) {
+	d := msgPackDecoder{b: sample}
+	v, err := d.decode()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.b) {
+		return nil, fmt.Errorf("msgpack: unexpected trailing data at offset %v", d.pos)
+	}
+	return &Container{object: v}, nil
+}
+
+// MsgPack encodes an element as MessagePack. Object keys are sorted in order
+// for the output to be deterministic, and float64 values that are whole numbers
+// are encoded in the smallest integer format that holds them.
+//
+// Values of types that MessagePack has no representation for are encoded via
+// their JSON representation.
+func (g *Container) MsgPack() ([]byte, error) {
+	var e msgPackEncoder
+	if err := e.encode(g.Data()); err != nil {
+		return nil, err
+	}
+	return e.b, nil
+}
+
+//------------------------------------------------------------------------------
+
+var (
+	errMsgPackShort    = errors.New("msgpack: unexpected end of input")
+	errMsgPackMaxDepth = errors.New("msgpack: exceeded maximum nesting depth")
+)
+
+const msgPackMaxDepth = 10000
+
+type msgPackDecoder struct {
+	b     []byte
+	pos   int
+	depth int
+}
+
+func (d *msgPackDecoder) read(n int) ([]byte, error) {
+	if n < 0 || len(d.b)-d.pos < n {
+		return nil, errMsgPackShort
+	}
+	b := d.b[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *msgPackDecoder) readUint(n int) (uint64, error) {
+	b, err := d.read(n)
+	if err != nil {
+		return 0, err
+	}
+	switch n {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (d *msgPackDecoder) readLen(n int) (int, error) {
+	l, err := d.readUint(n)
+	if err != nil {
+		return 0, err
+	}
+	if l > uint64(len(d.b)-d.pos) {
+		return 0, errMsgPackShort
+	}
+	return int(l), nil
+}
+
+// exactInt returns an integer as a float64 if it can be represented exactly,
+// otherwise as a json.Number.
+func exactInt(i int64) interface{} {
+	if i > 1<<53 || i < -(1<<53) {
+		return json.Number(strconv.FormatInt(i, 10))
+	}
+	return float64(i)
+}
+
+func exactUint(u uint64) interface{} {
+	if u > 1<<53 {
+		return json.Number(strconv.FormatUint(u, 10))
+	}
+	return float64(u)
+}
+
+func (d *msgPackDecoder) decode() (interface{}, error) {
+	d.depth++
+	defer func() {
+		d.depth--
+	}()
+	if d.depth > msgPackMaxDepth {
+		return nil, errMsgPackMaxDepth
+	}
+
+	head, err := d.readUint(1)
+	if err != nil {
+		return nil, err
+	}
+	c := byte(head)
+
+	switch {
+	case c <= 0x7f:
+		return float64(c), nil
+	case c >= 0xe0:
+		return float64(int8(c)), nil
+	case c >= 0x80 && c <= 0x8f:
+		return d.decodeMap(int(c & 0x0f))
+	case c >= 0x90 && c <= 0x9f:
+		return d.decodeArray(int(c & 0x0f))
+	case c >= 0xa0 && c <= 0xbf:
+		b, err := d.read(int(c & 0x1f))
+		return string(b), err
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		l, err := d.readLen(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(l)
+		return append([]byte(nil), b...), err
+	case 0xc7, 0xc8, 0xc9:
+		l, err := d.readLen(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeExt(l)
+	case 0xca:
+		u, err := d.readUint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := d.readUint(8)
+		return math.Float64frombits(u), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.readUint(1 << (c - 0xcc))
+		return exactUint(u), err
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		n := 1 << (c - 0xd0)
+		u, err := d.readUint(n)
+		if err != nil {
+			return nil, err
+		}
+		shift := uint(64 - 8*n)
+		return exactInt(int64(u<<shift) >> shift), nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.decodeExt(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		l, err := d.readLen(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(l)
+		return string(b), err
+	case 0xdc, 0xdd:
+		l, err := d.readLen(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(l)
+	case 0xde, 0xdf:
+		l, err := d.readLen(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(l)
+	}
+	return nil, fmt.Errorf("msgpack: invalid type byte 0x%x at offset %v", c, d.pos-1)
+}
+
+func (d *msgPackDecoder) decodeArray(l int) (interface{}, error) {
+	if l > len(d.b)-d.pos {
+		return nil, errMsgPackShort
+	}
+	arr := make([]interface{}, l)
+	for i := range arr {
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		arr[i] = v
+	}
+	return arr, nil
+}
+
+func (d *msgPackDecoder) decodeMap(l int) (interface{}, error) {
+	if l > len(d.b)-d.pos {
+		return nil, errMsgPackShort
+	}
+	m := make(map[string]interface{}, l)
+	for i := 0; i < l; i++ {
+		k, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		m[stringifyKey(k)] = v
+	}
+	return m, nil
+}
+
+func (d *msgPackDecoder) decodeExt(l int) (interface{}, error) {
+	t, err := d.readUint(1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := d.read(l)
+	if err != nil {
+		return nil, err
+	}
+	if int8(t) != -1 {
+		return MsgPackExt{Type: int8(t), Data: append([]byte(nil), data...)}, nil
+	}
+	switch l {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(data)), 0).UTC(), nil
+	case 8:
+		v := binary.BigEndian.Uint64(data)
+		return time.Unix(int64(v&0x3ffffffff), int64(v>>34)).UTC(), nil
+	case 12:
+		nsec := binary.BigEndian.Uint32(data[:4])
+		sec := int64(binary.BigEndian.Uint64(data[4:]))
+		return time.Unix(sec, int64(nsec)).UTC(), nil
+	}
+	return nil, fmt.Errorf("msgpack: invalid timestamp length %v", l)
+}
+
+//------------------------------------------------------------------------------
+
+type msgPackEncoder struct {
+	b []byte
+}
+
+func (e *msgPackEncoder) writeUint(prefix byte, n int, v uint64) {
+	e.b = append(e.b, prefix)
+	for i := n - 1; i >= 0; i-- {
+		e.b = append(e.b, byte(v>>(8*uint(i))))
+	}
+}
+
+// writeLen writes the header of a length prefixed type, where fixMax is the
+// maximum length of the fix variant (or zero if there isn't one) and prefixes
+// are the 8, 16 and 32 bit variants.
+func (e *msgPackEncoder) writeLen(fix byte, fixMax, l int, prefixes [3]byte) {
+	switch {
+	case l <= fixMax:
+		e.b = append(e.b, fix|byte(l))
+	case l <= math.MaxUint8 && prefixes[0] != 0:
+		e.writeUint(prefixes[0], 1, uint64(l))
+	case l <= math.MaxUint16:
+		e.writeUint(prefixes[1], 2, uint64(l))
+	default:
+		e.writeUint(prefixes[2], 4, uint64(l))
+	}
+}
+
+func (e *msgPackEncoder) encodeInt(i int64) {
+	switch {
+	case i >= 0:
+		e.encodeUint(uint64(i))
+	case i >= -32:
+		e.b = append(e.b, byte(int8(i)))
+	case i >= math.MinInt8:
+		e.writeUint(0xd0, 1, uint64(i))
+	case i >= math.MinInt16:
+		e.writeUint(0xd1, 2, uint64(i))
+	case i >= math.MinInt32:
+		e.writeUint(0xd2, 4, uint64(i))
+	default:
+		e.writeUint(0xd3, 8, uint64(i))
+	}
+}
+
+func (e *msgPackEncoder) encodeUint(u uint64) {
+	switch {
+	case u <= 0x7f:
+		e.b = append(e.b, byte(u))
+	case u <= math.MaxUint8:
+		e.writeUint(0xcc, 1, u)
+	case u <= math.MaxUint16:
+		e.writeUint(0xcd, 2, u)
+	case u <= math.MaxUint32:
+		e.writeUint(0xce, 4, u)
+	default:
+		e.writeUint(0xcf, 8, u)
+	}
+}
+
+func (e *msgPackEncoder) encodeFloat(f float64) {
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 && !(f == 0 && math.Signbit(f)) {
+		e.encodeInt(int64(f))
+		return
+	}
+	if f32 := float32(f); float64(f32) == f || math.IsNaN(f) {
+		e.writeUint(0xca, 4, uint64(math.Float32bits(f32)))
+		return
+	}
+	e.writeUint(0xcb, 8, math.Float64bits(f))
+}
+
+func (e *msgPackEncoder) encodeExt(t int8, data []byte) {
+	switch l := len(data); l {
+	case 1:
+		e.b = append(e.b, 0xd4)
+	case 2:
+		e.b = append(e.b, 0xd5)
+	case 4:
+		e.b = append(e.b, 0xd6)
+	case 8:
+		e.b = append(e.b, 0xd7)
+	case 16:
+		e.b = append(e.b, 0xd8)
+	default:
+		e.writeLen(0, -1, l, [3]byte{0xc7, 0xc8, 0xc9})
+	}
+	e.b = append(e.b, byte(t))
+	e.b = append(e.b, data...)
+}
+
+func (e *msgPackEncoder) encodeTime(t time.Time) {
+	sec, nsec := t.Unix(), int64(t.Nanosecond())
+	switch {
+	case sec>>34 == 0 && nsec == 0 && sec <= math.MaxUint32:
+		data := make([]byte, 4)
+		binary.BigEndian.PutUint32(data, uint32(sec))
+		e.encodeExt(-1, data)
+	case sec>>34 == 0:
+		data := make([]byte, 8)
+		binary.BigEndian.PutUint64(data, uint64(nsec)<<34|uint64(sec))
+		e.encodeExt(-1, data)
+	default:
+		data := make([]byte, 12)
+		binary.BigEndian.PutUint32(data[:4], uint32(nsec))
+		binary.BigEndian.PutUint64(data[4:], uint64(sec))
+		e.encodeExt(-1, data)
+	}
+}
+
+func (e *msgPackEncoder) encode(v interface{}) error {
+	switch t := v.(type) {
+	case nil:
+		e.b = append(e.b, 0xc0)
+	case bool:
+		if t {
+			e.b = append(e.b, 0xc3)
+		} else {
+			e.b = append(e.b, 0xc2)
+		}
+	case string:
+		e.writeLen(0xa0, 31, len(t), [3]byte{0xd9, 0xda, 0xdb})
+		e.b = append(e.b, t...)
+	case []byte:
+		e.writeLen(0, -1, len(t), [3]byte{0xc4, 0xc5, 0xc6})
+		e.b = append(e.b, t...)
+	case float64:
+		e.encodeFloat(t)
+	case float32:
+		e.encodeFloat(float64(t))
+	case int:
+		e.encodeInt(int64(t))
+	case int8:
+		e.encodeInt(int64(t))
+	case int16:
+		e.encodeInt(int64(t))
+	case int32:
+		e.encodeInt(int64(t))
+	case int64:
+		e.encodeInt(t)
+	case uint:
+		e.encodeUint(uint64(t))
+	case uint8:
+		e.encodeUint(uint64(t))
+	case uint16:
+		e.encodeUint(uint64(t))
+	case uint32:
+		e.encodeUint(uint64(t))
+	case uint64:
+		e.encodeUint(t)
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			e.encodeInt(i)
+		} else if u, err := strconv.ParseUint(string(t), 10, 64); err == nil {
+			e.encodeUint(u)
+		} else if f, err := t.Float64(); err == nil {
+			e.encodeFloat(f)
+		} else {
+			return fmt.Errorf("msgpack: invalid number literal '%v'", t)
+		}
+	case time.Time:
+		e.encodeTime(t)
+	case MsgPackExt:
+		e.encodeExt(t.Type, t.Data)
+	case []interface{}:
+		e.writeLen(0x90, 15, len(t), [3]byte{0, 0xdc, 0xdd})
+		for _, ele := range t {
+			if err := e.encode(ele); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		e.writeLen(0x80, 15, len(t), [3]byte{0, 0xde, 0xdf})
+		for _, k := range keys {
+			if err := e.encode(k); err != nil {
+				return err
+			}
+			if err := e.encode(t[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		return e.encode(generic)
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
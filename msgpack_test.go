@@ -0,0 +1,155 @@
+package gabs
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMsgPackRoundTrip(t *testing.T) {
+	c, err := ParseJSON([]byte(`{
+		"str": "hello world",
+		"long": "this string is longer than thirty one bytes for sure",
+		"ints": [0, 1, 127, 128, 255, 256, 65535, 65536, 4294967296, -1, -32, -33, -128, -129, -32768, -32769, -2147483649],
+		"floats": [0.5, 1.1, -2.25, 1e300],
+		"bools": [true, false],
+		"null": null,
+		"nested": {"arr": [{"a": "b"}], "empty": {}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := c.MsgPack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) >= len(c.Bytes()) {
+		t.Errorf("Expected msgpack to be smaller than JSON: %v >= %v", len(b), len(c.Bytes()))
+	}
+
+	decoded, err := ParseMsgPack(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := c.String(), decoded.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func TestMsgPackEncode(t *testing.T) {
+	type testCase struct {
+		input  interface{}
+		output []byte
+	}
+	tests := []testCase{
+		{input: nil, output: []byte{0xc0}},
+		{input: true, output: []byte{0xc3}},
+		{input: float64(5), output: []byte{0x05}},
+		{input: float64(-5), output: []byte{0xfb}},
+		{input: 300, output: []byte{0xcd, 0x01, 0x2c}},
+		{input: 1.5, output: []byte{0xca, 0x3f, 0xc0, 0x00, 0x00}},
+		{input: json.Number("-200"), output: []byte{0xd1, 0xff, 0x38}},
+		{input: "ab", output: []byte{0xa2, 'a', 'b'}},
+		{input: []byte{1, 2}, output: []byte{0xc4, 0x02, 0x01, 0x02}},
+		{input: []interface{}{1, "a"}, output: []byte{0x92, 0x01, 0xa1, 'a'}},
+		{input: map[string]interface{}{"b": 2, "a": 1}, output: []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x02}},
+		{input: MsgPackExt{Type: 5, Data: []byte{9}}, output: []byte{0xd4, 0x05, 0x09}},
+		{input: MsgPackExt{Type: 5, Data: []byte{1, 2, 3}}, output: []byte{0xc7, 0x03, 0x05, 1, 2, 3}},
+		{input: time.Unix(1, 0), output: []byte{0xd6, 0xff, 0, 0, 0, 1}},
+		{input: struct {
+			A int `json:"a"`
+		}{A: 1}, output: []byte{0x81, 0xa1, 'a', 0x01}},
+	}
+	for i, test := range tests {
+		b, err := Wrap(test.input).MsgPack()
+		if err != nil {
+			t.Errorf("[%d] Failed to encode: %v", i, err)
+			continue
+		}
+		if !bytes.Equal(test.output, b) {
+			t.Errorf("[%d] Wrong result: %x != %x", i, b, test.output)
+		}
+	}
+}
+
+func TestMsgPackDecodeTypes(t *testing.T) {
+	input := []byte{
+		0x87,
+		0xa3, 'b', 'i', 'n', 0xc4, 0x02, 0x01, 0x02,
+		0xa3, 'e', 'x', 't', 0xd5, 0x07, 0x01, 0x02,
+		0xa3, 'b', 'i', 'g', 0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xa3, 'n', 'e', 'g', 0xd3, 0x80, 0, 0, 0, 0, 0, 0, 0,
+		0xa2, 't', 's', 0xd7, 0xff, 0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x01,
+		0x01, 0xa3, 'o', 'n', 'e',
+		0xa3, 'f', '6', '4', 0xcb, 0x3f, 0xf1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a,
+	}
+	c, err := ParseMsgPack(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exp, act := []byte{1, 2}, c.Path("bin").Data(); !bytes.Equal(exp, act.([]byte)) {
+		t.Errorf("Wrong bin: %v != %v", act, exp)
+	}
+	if exp, act := (MsgPackExt{Type: 7, Data: []byte{1, 2}}), c.Path("ext").Data().(MsgPackExt); exp.Type != act.Type || !bytes.Equal(exp.Data, act.Data) {
+		t.Errorf("Wrong ext: %v != %v", act, exp)
+	}
+	if exp, act := json.Number("18446744073709551615"), c.Path("big").Data(); exp != act {
+		t.Errorf("Wrong big: %v != %v", act, exp)
+	}
+	if exp, act := json.Number("-9223372036854775808"), c.Path("neg").Data(); exp != act {
+		t.Errorf("Wrong neg: %v != %v", act, exp)
+	}
+	if exp, act := time.Unix(1, 1).UTC(), c.Path("ts").Data().(time.Time); !exp.Equal(act) {
+		t.Errorf("Wrong ts: %v != %v", act, exp)
+	}
+	if exp, act := "one", c.Path("1").Data(); exp != act {
+		t.Errorf("Wrong key conversion: %v != %v", act, exp)
+	}
+	if exp, act := 1.1, c.Path("f64").Data(); exp != act {
+		t.Errorf("Wrong float: %v != %v", act, exp)
+	}
+
+	b, err := c.MsgPack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTrip, err := ParseMsgPack(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(c, roundTrip) {
+		t.Errorf("Round trip mismatch: %v != %v", roundTrip, c)
+	}
+}
+
+func TestMsgPackDecodeErrors(t *testing.T) {
+	tests := [][]byte{
+		{},
+		{0xc1},
+		{0x92, 0x01},
+		{0xa5, 'a'},
+		{0xdb, 0xff, 0xff, 0xff, 0xff},
+		{0x01, 0x02},
+		{0xd6, 0xff, 0x00},
+	}
+	for i, test := range tests {
+		if _, err := ParseMsgPack(test); err == nil {
+			t.Errorf("[%d] Expected error", i)
+		}
+	}
+
+	if _, err := ParseMsgPack(bytes.Repeat([]byte{0x91}, 2<<20)); err != errMsgPackMaxDepth {
+		t.Errorf("Expected depth error, received: %v", err)
+	}
+
+	if _, err := Wrap(math.NaN()).MsgPack(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := Wrap(make(chan int)).MsgPack(); err == nil {
+		t.Error("Expected error")
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+//------------------------------------------------------------------------------
+
+// NDJSONOpt is a functional option for the NewNDJSONReader function.
+type NDJSONOpt func(o *ndjsonOpts)
+
+type ndjsonOpts struct {
+	skipMalformed bool
+}
+
+// NDJSONOptSkipMalformed causes lines that cannot be parsed as JSON to be
+// skipped rather than returned as errors. The number of skipped lines can be
+// obtained with NDJSONReader.Skipped.
+func NDJSONOptSkipMalformed() NDJSONOpt {
+	return func(o *ndjsonOpts) {
+		o.skipMalformed = true
+	}
+}
+
+// NDJSONReader reads a stream of newline delimited JSON values, yielding a
+// *Container for each line. Blank lines are ignored.
+type NDJSONReader struct {
+	r       *bufio.Reader
+	opts    ndjsonOpts
+	line    int
+	skipped int
+}
+
+// NewNDJSONReader creates a reader of newline delimited JSON values from r.
+// Lines are not limited in length.
+func NewNDJSONReader(r io.Reader, opts ...NDJSONOpt) *NDJSONReader {
+	n := &NDJSONReader{r: bufio.NewReader(r)}
+	for _, opt := range opts {
+		opt(&n.opts)
+	}
+	return n
+}
+
+// Next parses the next line of the stream into a *Container. Returns io.EOF
+// once the stream is exhausted. Errors from malformed lines include the line
+// number and do not prevent subsequent calls from reading further lines.
+func (n *NDJSONReader) Next() (*Container, error) {
+	for {
+		line, readErr := n.r.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return nil, readErr
+		}
+		if len(line) == 0 && readErr == io.EOF {
+			return nil, io.EOF
+		}
+		n.line++
+
+		if line = bytes.TrimSpace(line); len(line) == 0 {
+			continue
+		}
+		c, err := ParseJSON(line)
+		if err != nil {
+			if n.opts.skipMalformed {
+				n.skipped++
+				continue
+			}
+			return nil, fmt.Errorf("line %v: %w", n.line, err)
+		}
+		return c, nil
+	}
+}
+
+// Line returns the line number of the most recently read line, starting at 1.
+func (n *NDJSONReader) Line() int {
+	return n.line
+}
+
+// Skipped returns the number of malformed lines that were skipped.
+func (n *NDJSONReader) Skipped() int {
+	return n.skipped
+}
+
+//------------------------------------------------------------------------------
+
+// NDJSONWriter writes containers as a stream of newline delimited JSON values.
+type NDJSONWriter struct {
+	w    io.Writer
+	opts []EncodeOpt
+	buf  bytes.Buffer
+}
+
+// NewNDJSONWriter creates a writer of newline delimited JSON values to w,
+// where values are encoded with the same options as EncodeJSON. Since each
+// value must occupy a single line, any indentation is removed.
+func NewNDJSONWriter(w io.Writer, opts ...EncodeOpt) *NDJSONWriter {
+	return &NDJSONWriter{w: w, opts: opts}
+}
+
+// Write encodes a container as a single line and writes it.
+func (n *NDJSONWriter) Write(c *Container) error {
+	n.buf.Reset()
+	encoder := json.NewEncoder(&n.buf)
+	encoder.SetEscapeHTML(false)
+	for _, opt := range n.opts {
+		opt(encoder)
+	}
+	if err := encoder.Encode(c.Data()); err != nil {
+		return err
+	}
+
+	line := n.buf.Bytes()
+	if bytes.IndexByte(line[:len(line)-1], '\n') >= 0 {
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, line); err != nil {
+			return err
+		}
+		compacted.WriteByte('\n')
+		line = compacted.Bytes()
+	}
+	_, err := n.w.Write(line)
+	return err
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,124 @@
+package gabs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestNDJSONReader(t *testing.T) {
+	input := "{\"a\":1}\n\n  [1,2]  \r\n\"str\"\n{\"b\":{\"c\":true}}"
+
+	r := NewNDJSONReader(strings.NewReader(input))
+	var results []string
+	var lines []int
+	for {
+		c, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, c.String())
+		lines = append(lines, r.Line())
+	}
+
+	if exp, act := `{"a":1}|[1,2]|"str"|{"b":{"c":true}}`, strings.Join(results, "|"); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := "[1 3 4 5]", fmt.Sprint(lines); exp != act {
+		t.Errorf("Wrong lines: %v != %v", act, exp)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF, received: %v", err)
+	}
+}
+
+func TestNDJSONReaderMalformed(t *testing.T) {
+	input := "{\"a\":1}\n{\"a\":\n{\"a\":3} {}\n{\"a\":4}\n"
+
+	r := NewNDJSONReader(strings.NewReader(input))
+	if _, err := r.Next(); err != nil {
+		t.Fatal(err)
+	}
+	_, err := r.Next()
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("Expected line 2 error, received: %v", err)
+	}
+	_, err = r.Next()
+	if err == nil || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Errorf("Expected line 3 error, received: %v", err)
+	}
+	c, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"a":4}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	r = NewNDJSONReader(strings.NewReader(input), NDJSONOptSkipMalformed())
+	var results []string
+	for {
+		c, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, c.String())
+	}
+	if exp, act := `{"a":1}|{"a":4}`, strings.Join(results, "|"); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := 2, r.Skipped(); exp != act {
+		t.Errorf("Wrong skipped count: %v != %v", act, exp)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestNDJSONReaderError(t *testing.T) {
+	r := NewNDJSONReader(errReader{}, NDJSONOptSkipMalformed())
+	if _, err := r.Next(); err == nil || err.Error() != "read failed" {
+		t.Errorf("Expected read error, received: %v", err)
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := NewNDJSONWriter(&buf)
+	for _, c := range []*Container{
+		Wrap(map[string]interface{}{"a": "<b>", "c": []interface{}{1, 2}}),
+		Wrap("line\nbreak"),
+		Wrap(nil),
+	} {
+		if err := w.Write(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w = NewNDJSONWriter(&buf, EncodeOptHTMLEscape(true), EncodeOptIndent("", "  "))
+	if err := w.Write(Wrap(map[string]interface{}{"a": "<b>", "c": []interface{}{1, 2}})); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := "{\"a\":\"<b>\",\"c\":[1,2]}\n\"line\\nbreak\"\nnull\n{\"a\":\"\\u003cb\\u003e\",\"c\":[1,2]}\n"
+	if act := buf.String(); exp != act {
+		t.Errorf("Wrong result: %q != %q", act, exp)
+	}
+
+	if err := w.Write(Wrap(func() {})); err == nil {
+		t.Error("Expected error from unsupported type")
+	}
+}
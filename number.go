@@ -0,0 +1,92 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+//------------------------------------------------------------------------------
+
+// ParseOpt is a functional option for the ParseJSONWithOpts family of functions
+// and ParseJSONLazy.
+type ParseOpt func(o *parseOpts)
+
+type parseOpts struct {
+	useNumber bool
+}
+
+func newParseOpts(opts []ParseOpt) *parseOpts {
+	o := &parseOpts{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// ParseOptUseNumber causes numbers to be parsed into json.Number values rather
+// than float64. A json.Number retains the exact text of its literal, and
+// therefore values such as 1.10, 1e3 and integers beyond the precision of a
+// float64 are encoded back out exactly as they were received.
+func ParseOptUseNumber() ParseOpt {
+	return func(o *parseOpts) {
+		o.useNumber = true
+	}
+}
+
+// unmarshal decodes a single JSON value from data into v.
+func (o *parseOpts) unmarshal(data []byte, v interface{}) error {
+	if o == nil || !o.useNumber {
+		return json.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("invalid data after top-level value at offset %v", dec.InputOffset())
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// NumberLiteral validates a JSON number literal and returns it as a
+// json.Number, which can be set within a container in order to encode the
+// literal exactly. Returns an error if the literal is not a valid JSON number.
+func NumberLiteral(literal string) (json.Number, error) {
+	var v interface{}
+	if err := (&parseOpts{useNumber: true}).unmarshal([]byte(literal), &v); err == nil {
+		if n, ok := v.(json.Number); ok {
+			return n, nil
+		}
+	}
+	return "", fmt.Errorf("invalid number literal '%v'", literal)
+}
+
+//------------------------------------------------------------------------------
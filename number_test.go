@@ -0,0 +1,118 @@
+package gabs
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseOptUseNumber(t *testing.T) {
+	input := `{"price":1.10,"qty":1e3,"id":12345678901234567890,"neg":-0.0,"list":[0.10,2]}`
+
+	c, err := ParseJSONWithOpts([]byte(input), ParseOptUseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := json.Number("1.10"), c.Path("price").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := `{"id":12345678901234567890,"list":[0.10,2],"neg":-0.0,"price":1.10,"qty":1e3}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	if c, err = ParseJSON([]byte(input)); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"id":12345678901234567000,"list":[0.1,2],"neg":-0,"price":1.1,"qty":1000}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	for i, test := range []string{``, `{"a":1} {}`, `{"a":1}x`, `{"a":}`} {
+		if _, err = ParseJSONWithOpts([]byte(test), ParseOptUseNumber()); err == nil {
+			t.Errorf("[%d] Expected error from '%v'", i, test)
+		}
+	}
+}
+
+func TestParseOptUseNumberSources(t *testing.T) {
+	input := `{"a":1.10,"b":{"c":1e3}}`
+
+	path := filepath.Join(t.TempDir(), "numbers.json")
+	if err := os.WriteFile(path, []byte(input), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := ParseJSONFileWithOpts(path, ParseOptUseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := input, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	if c, err = ParseJSONBufferWithOpts(bytes.NewReader([]byte(input)), ParseOptUseNumber()); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := json.Number("1e3"), c.Path("b.c").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	if c, err = ParseJSONLazy([]byte(input), ParseOptUseNumber()); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := json.Number("1.10"), c.Path("a").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := json.Number("1e3"), c.Data().(map[string]interface{})["b"].(map[string]interface{})["c"]; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func TestNumberLiteral(t *testing.T) {
+	for i, test := range []string{"0", "-1", "1.10", "1e3", "-2.5E-10", "123456789012345678901234567890"} {
+		n, err := NumberLiteral(test)
+		if err != nil {
+			t.Errorf("[%d] Unexpected error: %v", i, err)
+			continue
+		}
+		c := New()
+		if _, err = c.Set(n, "n"); err != nil {
+			t.Fatal(err)
+		}
+		if exp, act := `{"n":`+test+`}`, c.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+
+	for i, test := range []string{"", "01", "1.", ".5", "+1", "1e", "NaN", `"1"`, "1 2", "0x10"} {
+		if _, err := NumberLiteral(test); err == nil {
+			t.Errorf("[%d] Expected error from '%v'", i, test)
+		}
+	}
+}
+
+func TestUnmarshalJSONUseNumber(t *testing.T) {
+	type request struct {
+		Body *Container `json:"body"`
+	}
+
+	req := request{Body: NewWithOpts(ParseOptUseNumber())}
+	if err := json.Unmarshal([]byte(`{"body":{"id":12345678901234567890,"price":1.10}}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := json.Number("1.10"), req.Body.Path("price").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	c, err := ParseJSONWithOpts([]byte(`{}`), ParseOptUseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.UnmarshalJSON([]byte(`[1.0]`)); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := json.Number("1.0"), c.Index(0).Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
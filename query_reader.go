@@ -0,0 +1,396 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+//------------------------------------------------------------------------------
+
+// QueryReader scans a JSON document from r and extracts the values found at
+// dot notation paths, returning them within a container at the same paths.
+// Arrays along a path are represented in the result as objects keyed by index,
+// and therefore values can be accessed from the result with the same paths
+// that were queried. Paths that do not exist within the document are absent
+// from the result.
+//
+// Values that are not on the path to a query are skipped without being
+// decoded, and reading stops as soon as all paths have been found, in which
+// case the remainder of the document is neither read nor validated. If an
+// object contains duplicate keys the first occurrence is used.
+func QueryReader(r io.Reader, paths ...string) (*Container, error) {
+	q := queryReader{
+		s:      jsonScanner{r: bufio.NewReader(r)},
+		root:   &queryNode{},
+		result: New(),
+	}
+	for _, p := range paths {
+		q.add(DotPathToSlice(p))
+	}
+	if q.remaining == 0 {
+		return q.result, nil
+	}
+
+	b, err := q.s.nextNonSpace()
+	if err != nil {
+		return nil, err
+	}
+	if err = q.walk(q.root, nil, b); err != nil && err != errQueryComplete {
+		return nil, err
+	}
+	return q.result, nil
+}
+
+//------------------------------------------------------------------------------
+
+var errQueryComplete = errors.New("query complete")
+
+type queryNode struct {
+	children map[string]*queryNode
+	indexes  map[int]*queryNode
+	leaf     bool
+	found    bool
+}
+
+type queryReader struct {
+	s         jsonScanner
+	root      *queryNode
+	result    *Container
+	remaining int
+}
+
+func (q *queryReader) add(hierarchy []string) {
+	node := q.root
+	for _, seg := range hierarchy {
+		if node.leaf {
+			// A parent of this path is already queried.
+			return
+		}
+		if node.children == nil {
+			node.children = map[string]*queryNode{}
+		}
+		child, exists := node.children[seg]
+		if !exists {
+			child = &queryNode{}
+			node.children[seg] = child
+			if index, err := strconv.Atoi(seg); err == nil && index >= 0 && strconv.Itoa(index) == seg {
+				if node.indexes == nil {
+					node.indexes = map[int]*queryNode{}
+				}
+				node.indexes[index] = child
+			}
+		}
+		node = child
+	}
+	if !node.leaf {
+		q.remaining += 1 - countQueryLeaves(node)
+		node.leaf, node.children, node.indexes = true, nil, nil
+	}
+}
+
+func countQueryLeaves(n *queryNode) int {
+	if n.leaf {
+		return 1
+	}
+	count := 0
+	for _, c := range n.children {
+		count += countQueryLeaves(c)
+	}
+	return count
+}
+
+// walk consumes a value beginning with the byte b.
+func (q *queryReader) walk(node *queryNode, hierarchy []string, b byte) error {
+	if node.leaf {
+		raw, err := q.s.captureValue(b)
+		if err != nil {
+			return err
+		}
+		var v interface{}
+		if err = json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		if _, err = q.result.Set(v, hierarchy...); err != nil {
+			return err
+		}
+		node.found = true
+		if q.remaining--; q.remaining == 0 {
+			return errQueryComplete
+		}
+		return nil
+	}
+
+	switch b {
+	case '{':
+		return q.s.eachMember(func(key []byte, b byte) error {
+			if child := node.children[string(key)]; child != nil && !child.found {
+				return q.walk(child, append(hierarchy[:len(hierarchy):len(hierarchy)], string(key)), b)
+			}
+			return q.s.skipValue(b)
+		})
+	case '[':
+		index := 0
+		return q.s.eachElement(func(b byte) error {
+			child := node.indexes[index]
+			index++
+			if child != nil && !child.found {
+				return q.walk(child, append(hierarchy[:len(hierarchy):len(hierarchy)], strconv.Itoa(index-1)), b)
+			}
+			return q.s.skipValue(b)
+		})
+	}
+	return q.s.skipValue(b)
+}
+
+//------------------------------------------------------------------------------
+
+// jsonScanner reads JSON from a stream at the byte level, allowing values to
+// be skipped without allocating them. Skipped values are only checked for
+// balanced structure.
+type jsonScanner struct {
+	r         *bufio.Reader
+	capturing bool
+	buf       []byte
+	offset    int
+}
+
+func (s *jsonScanner) readByte() (byte, error) {
+	b, err := s.r.ReadByte()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+	s.offset++
+	if s.capturing {
+		s.buf = append(s.buf, b)
+	}
+	return b, nil
+}
+
+func (s *jsonScanner) unreadByte() {
+	_ = s.r.UnreadByte()
+	s.offset--
+	if s.capturing {
+		s.buf = s.buf[:len(s.buf)-1]
+	}
+}
+
+func (s *jsonScanner) syntaxError(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid JSON at offset %v: %v", s.offset, fmt.Sprintf(format, args...))
+}
+
+func (s *jsonScanner) nextNonSpace() (byte, error) {
+	for {
+		b, err := s.readByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		return b, nil
+	}
+}
+
+// skipString consumes the remainder of a string following its opening quote.
+func (s *jsonScanner) skipString() error {
+	for {
+		b, err := s.readByte()
+		if err != nil {
+			return err
+		}
+		switch b {
+		case '"':
+			return nil
+		case '\\':
+			if _, err = s.readByte(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readString consumes the remainder of a string following its opening quote
+// and returns its unquoted value, which is only valid until the next read.
+func (s *jsonScanner) readString() ([]byte, error) {
+	wasCapturing, start := s.capturing, len(s.buf)
+	s.capturing = true
+	s.buf = append(s.buf, '"')
+	err := s.skipString()
+	raw := s.buf[start:]
+	s.capturing = wasCapturing
+	if !wasCapturing {
+		s.buf = s.buf[:start]
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(raw, '\\') < 0 {
+		return raw[1 : len(raw)-1], nil
+	}
+	var str string
+	if err = json.Unmarshal(raw, &str); err != nil {
+		return nil, s.syntaxError("%v", err)
+	}
+	return []byte(str), nil
+}
+
+// skipValue consumes a value beginning with the byte b.
+func (s *jsonScanner) skipValue(b byte) error {
+	switch b {
+	case '"':
+		return s.skipString()
+	case '{', '[':
+		depth := 1
+		for depth > 0 {
+			c, err := s.readByte()
+			if err != nil {
+				return err
+			}
+			switch c {
+			case '"':
+				if err = s.skipString(); err != nil {
+					return err
+				}
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+		return nil
+	case '}', ']', ',', ':':
+		return s.syntaxError("unexpected character '%c'", b)
+	}
+	// Literals and numbers run until the next delimiter.
+	for {
+		c, err := s.r.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.offset++
+		if s.capturing {
+			s.buf = append(s.buf, c)
+		}
+		switch c {
+		case ' ', '\t', '\n', '\r', ',', '}', ']':
+			s.unreadByte()
+			return nil
+		}
+	}
+}
+
+// captureValue consumes a value beginning with the byte b and returns its raw
+// bytes.
+func (s *jsonScanner) captureValue(b byte) ([]byte, error) {
+	s.capturing = true
+	s.buf = append(s.buf[:0], b)
+	err := s.skipValue(b)
+	s.capturing = false
+	return s.buf, err
+}
+
+// eachMember consumes the remainder of an object following its opening brace,
+// calling fn for each member with its key and the first byte of its value.
+// The function must consume the value, and the key is only valid until then.
+func (s *jsonScanner) eachMember(fn func(key []byte, b byte) error) error {
+	b, err := s.nextNonSpace()
+	if err != nil || b == '}' {
+		return err
+	}
+	for {
+		if b != '"' {
+			return s.syntaxError("expected object key, found '%c'", b)
+		}
+		key, err := s.readString()
+		if err != nil {
+			return err
+		}
+		if b, err = s.nextNonSpace(); err != nil {
+			return err
+		}
+		if b != ':' {
+			return s.syntaxError("expected ':' after object key, found '%c'", b)
+		}
+		if b, err = s.nextNonSpace(); err != nil {
+			return err
+		}
+		if err = fn(key, b); err != nil {
+			return err
+		}
+		if b, err = s.nextNonSpace(); err != nil {
+			return err
+		}
+		switch b {
+		case '}':
+			return nil
+		case ',':
+			if b, err = s.nextNonSpace(); err != nil {
+				return err
+			}
+		default:
+			return s.syntaxError("expected ',' or '}' within object, found '%c'", b)
+		}
+	}
+}
+
+// eachElement consumes the remainder of an array following its opening
+// bracket, calling fn with the first byte of each element. The function must
+// consume the element.
+func (s *jsonScanner) eachElement(fn func(b byte) error) error {
+	b, err := s.nextNonSpace()
+	if err != nil || b == ']' {
+		return err
+	}
+	for {
+		if err = fn(b); err != nil {
+			return err
+		}
+		if b, err = s.nextNonSpace(); err != nil {
+			return err
+		}
+		switch b {
+		case ']':
+			return nil
+		case ',':
+			if b, err = s.nextNonSpace(); err != nil {
+				return err
+			}
+		default:
+			return s.syntaxError("expected ',' or ']' within array, found '%c'", b)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,132 @@
+package gabs
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestQueryReader(t *testing.T) {
+	type testCase struct {
+		input  string
+		paths  []string
+		output string
+	}
+	tests := []testCase{
+		{
+			input:  `{"id": "abc", "meta": {"type": "push", "size": 3}, "payload": {"big": [1, 2, {"x": "}]\"{"}]}}`,
+			paths:  []string{"id", "meta.type"},
+			output: `{"id":"abc","meta":{"type":"push"}}`,
+		},
+		{
+			input:  `{"a": {"b": 1, "c": 2}}`,
+			paths:  []string{"a.b", "a", "missing"},
+			output: `{"a":{"b":1,"c":2}}`,
+		},
+		{
+			input:  `{"items": [{"id": 1}, {"id": 2}, {"id": 3}]}`,
+			paths:  []string{"items.1.id"},
+			output: `{"items":{"1":{"id":2}}}`,
+		},
+		{
+			input:  `{"esc\"aped": true, "unié": [null, false]}`,
+			paths:  []string{"esc\"aped", "unié.1"},
+			output: `{"esc\"aped":true,"unié":{"1":false}}`,
+		},
+		{
+			input:  `{"a": 1, "a": 2}`,
+			paths:  []string{"a"},
+			output: `{"a":1}`,
+		},
+		{
+			input:  `[1, 2, 3]`,
+			paths:  []string{"2"},
+			output: `{"2":3}`,
+		},
+		{
+			input:  `"str"`,
+			paths:  []string{"a"},
+			output: `{}`,
+		},
+		{
+			input:  `{"a": -1.5e3}`,
+			paths:  []string{"a"},
+			output: `{"a":-1500}`,
+		},
+		{
+			input:  `{}`,
+			paths:  nil,
+			output: `{}`,
+		},
+	}
+
+	for i, test := range tests {
+		c, err := QueryReader(strings.NewReader(test.input), test.paths...)
+		if err != nil {
+			t.Errorf("[%d] Failed to query: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, c.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+// failingReader returns data followed by an error.
+type failingReader struct {
+	data string
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if len(f.data) == 0 {
+		return 0, errors.New("read past expected end")
+	}
+	n := copy(p, f.data)
+	f.data = f.data[n:]
+	return n, nil
+}
+
+func TestQueryReaderEarlyTermination(t *testing.T) {
+	c, err := QueryReader(&failingReader{data: `{"id": 5, "meta": {"type": "x"}, "rest": [1, 2`}, "meta.type", "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"id":5,"meta":{"type":"x"}}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func TestQueryReaderErrors(t *testing.T) {
+	tests := []string{
+		``,
+		`{"a": {"b": 1`,
+		`{"a" 1}`,
+		`{a: 1}`,
+		`{"b": 1 "a": 2}`,
+		`{"b": [1], ]`,
+		`{"a": tru}`,
+		`{"x": }`,
+	}
+
+	for i, test := range tests {
+		if _, err := QueryReader(strings.NewReader(test), "a"); err == nil {
+			t.Errorf("[%d] Expected error from '%v'", i, test)
+		}
+	}
+
+	if _, err := QueryReader(strings.NewReader(`{"b": `), "a"); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected unexpected EOF, received: %v", err)
+	}
+}
+
+func BenchmarkQueryReader(b *testing.B) {
+	doc := `{"id":"abc","meta":{"type":"push"},"payload":[` + strings.Repeat(`{"k":"value","n":[1,2,3]},`, 1000) + `{}]}`
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := QueryReader(strings.NewReader(doc), "payload.1000", "id"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
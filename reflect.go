@@ -0,0 +1,530 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//------------------------------------------------------------------------------
+
+// The functions within this file allow a container to navigate values of typed
+// Go structures such as map[string]string, []string or structs, which are
+// encountered when a document was decoded by something other than this
+// package. Typed maps, slices, arrays, pointers and exported struct fields are
+// all supported, where struct fields are named according to their json tags.
+
+var genericObjType = reflect.TypeOf(map[string]interface{}{})
+
+// reflectIndirect follows pointers and interfaces until a concrete value is
+// reached. Returns an invalid value if a nil pointer or interface is found.
+func reflectIndirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// reflectMapKey converts a path segment into a key of a map type, which is
+// possible for keys of a string or integer kind.
+func reflectMapKey(t reflect.Type, seg string) (reflect.Value, error) {
+	kt := t.Key()
+	switch kt.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(seg).Convert(kt), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(seg, 10, kt.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("segment value '%v' could not be parsed into a map key of type %v: %v", seg, kt, err)
+		}
+		return reflect.ValueOf(i).Convert(kt), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(seg, 10, kt.Bits())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("segment value '%v' could not be parsed into a map key of type %v: %v", seg, kt, err)
+		}
+		return reflect.ValueOf(u).Convert(kt), nil
+	}
+	return reflect.Value{}, fmt.Errorf("map key type %v is not supported", kt)
+}
+
+// reflectIndex converts a path segment into an index of a slice or array.
+func reflectIndex(v reflect.Value, seg string) (int, error) {
+	index, err := strconv.Atoi(seg)
+	if err != nil {
+		return 0, fmt.Errorf("found array but segment value '%v' could not be parsed into array index: %v", seg, err)
+	}
+	if index < 0 {
+		return 0, fmt.Errorf("found array but index '%v' is invalid", seg)
+	}
+	if v.Len() <= index {
+		return 0, fmt.Errorf("found array but index '%v' exceeded target array size of '%v'", seg, v.Len())
+	}
+	return index, nil
+}
+
+// reflectStructField finds an exported field of a struct by its JSON name,
+// following the same rules as encoding/json where a json tag name takes
+// precedence, fields of embedded structs are promoted, and names are matched
+// case insensitively when there is no exact match.
+func reflectStructField(v reflect.Value, name string) (reflect.Value, bool) {
+	var fold reflect.Value
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		fieldName := tag
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			fieldName = tag[:idx]
+		}
+		if sf.Anonymous && fieldName == "" {
+			if ev := reflectIndirect(v.Field(i)); ev.IsValid() && ev.Kind() == reflect.Struct {
+				if f, ok := reflectStructField(ev, name); ok {
+					return f, true
+				}
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if fieldName == "" {
+			fieldName = sf.Name
+		}
+		if fieldName == name {
+			return v.Field(i), true
+		}
+		if !fold.IsValid() && strings.EqualFold(fieldName, name) {
+			fold = v.Field(i)
+		}
+	}
+	return fold, fold.IsValid()
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	// codecValueTypes are the struct types of this package that represent a
+	// single value of a binary format.
+	codecValueTypes = map[reflect.Type]struct{}{
+		reflect.TypeOf(BSONBinary{}):    {},
+		reflect.TypeOf(BSONTimestamp{}): {},
+		reflect.TypeOf(BSONRegex{}):     {},
+		reflect.TypeOf(BSONMinKey{}):    {},
+		reflect.TypeOf(BSONMaxKey{}):    {},
+		reflect.TypeOf(MsgPackExt{}):    {},
+		reflect.TypeOf(CBORTag{}):       {},
+	}
+)
+
+// reflectIsScalar returns true for values that are treated as a single value
+// even though they are of a composite kind, which are byte slices, types that
+// implement json.Marshaler or encoding.TextMarshaler such as time.Time, and the
+// types of this package such as BSONBinary and MsgPackExt.
+func reflectIsScalar(v reflect.Value) bool {
+	t := v.Type()
+	if _, isCodecType := codecValueTypes[t]; isCodecType {
+		return true
+	}
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return true
+	}
+	if pt := reflect.PtrTo(t); pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType) {
+		return true
+	}
+	return v.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// reflectElements returns the elements of a typed slice or array as a generic
+// array. Returns false if the value is not a slice or array.
+func reflectElements(object interface{}) ([]interface{}, bool) {
+	v := reflectIndirect(reflect.ValueOf(object))
+	if !v.IsValid() || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || reflectIsScalar(v) {
+		return nil, false
+	}
+	elems := make([]interface{}, v.Len())
+	for i := range elems {
+		elems[i] = v.Index(i).Interface()
+	}
+	return elems, true
+}
+
+// reflectFields returns the values of a typed map with string or integer keys,
+// or the exported fields of a struct by their JSON names, as a generic object.
+// Returns false if the value is neither.
+func reflectFields(object interface{}) (map[string]interface{}, bool) {
+	v := reflectIndirect(reflect.ValueOf(object))
+	if !v.IsValid() || reflectIsScalar(v) {
+		return nil, false
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		fields := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			k := iter.Key()
+			switch k.Kind() {
+			case reflect.String:
+				fields[k.String()] = iter.Value().Interface()
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				fields[strconv.FormatInt(k.Int(), 10)] = iter.Value().Interface()
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				fields[strconv.FormatUint(k.Uint(), 10)] = iter.Value().Interface()
+			default:
+				return nil, false
+			}
+		}
+		return fields, true
+	case reflect.Struct:
+		fields := map[string]interface{}{}
+		reflectStructFields(v, fields)
+		return fields, true
+	}
+	return nil, false
+}
+
+// reflectStructFields adds the exported fields of a struct to an object by
+// their JSON names, following the same rules as reflectStructField, where the
+// fields of embedded structs are added unless they are shadowed.
+func reflectStructFields(v reflect.Value, fields map[string]interface{}) {
+	t := v.Type()
+	var embedded []reflect.Value
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		fieldName := tag
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			fieldName = tag[:idx]
+		}
+		if sf.Anonymous && fieldName == "" {
+			if ev := reflectIndirect(v.Field(i)); ev.IsValid() && ev.Kind() == reflect.Struct {
+				embedded = append(embedded, ev)
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if fieldName == "" {
+			fieldName = sf.Name
+		}
+		fields[fieldName] = v.Field(i).Interface()
+	}
+	for _, ev := range embedded {
+		promoted := map[string]interface{}{}
+		reflectStructFields(ev, promoted)
+		for k, f := range promoted {
+			if _, exists := fields[k]; !exists {
+				fields[k] = f
+			}
+		}
+	}
+}
+
+// reflectSearch resolves a single path segment against a typed Go value.
+func reflectSearch(object interface{}, seg string) (interface{}, error) {
+	v := reflectIndirect(reflect.ValueOf(object))
+	if !v.IsValid() {
+		return nil, fmt.Errorf("field '%v' was not found", seg)
+	}
+
+	var child reflect.Value
+	switch v.Kind() {
+	case reflect.Map:
+		key, err := reflectMapKey(v.Type(), seg)
+		if err != nil {
+			return nil, err
+		}
+		if child = v.MapIndex(key); !child.IsValid() {
+			return nil, fmt.Errorf("key '%v' was not found", seg)
+		}
+	case reflect.Slice, reflect.Array:
+		index, err := reflectIndex(v, seg)
+		if err != nil {
+			return nil, err
+		}
+		child = v.Index(index)
+	case reflect.Struct:
+		var ok bool
+		if child, ok = reflectStructField(v, seg); !ok || !child.CanInterface() {
+			return nil, fmt.Errorf("field '%v' was not found", seg)
+		}
+	default:
+		return nil, fmt.Errorf("field '%v' was not found", seg)
+	}
+	return child.Interface(), nil
+}
+
+// reflectAppend appends a value to a typed slice. When the value cannot be
+// stored as the element type of the slice, or the object is an array, the
+// elements are returned as a generic array with the value appended instead.
+// Returns false if the object is not a slice or array.
+func reflectAppend(object, value interface{}) (interface{}, bool) {
+	elems, ok := reflectElements(object)
+	if !ok {
+		return nil, false
+	}
+	if v := reflectIndirect(reflect.ValueOf(object)); v.Kind() == reflect.Slice {
+		if elem, err := reflectValueOf(value, v.Type().Elem()); err == nil {
+			return reflect.Append(v, elem).Interface(), true
+		}
+	}
+	return append(elems, value), true
+}
+
+// reflectDelete removes a key from a typed map in place and returns nil, or
+// removes an element from a typed slice and returns a copy of the slice
+// without it, which must be set in place of the original.
+func reflectDelete(object interface{}, seg string) (interface{}, error) {
+	v := reflectIndirect(reflect.ValueOf(object))
+	if !v.IsValid() || reflectIsScalar(v) {
+		return nil, ErrNotObjOrArray
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		key, err := reflectMapKey(v.Type(), seg)
+		if err != nil || !v.MapIndex(key).IsValid() {
+			return nil, ErrNotFound
+		}
+		v.SetMapIndex(key, reflect.Value{})
+		return nil, nil
+	case reflect.Slice:
+		index, err := strconv.Atoi(seg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse array index '%v': %v", seg, err)
+		}
+		if index < 0 || index >= v.Len() {
+			return nil, ErrOutOfBounds
+		}
+		shortened := reflect.MakeSlice(v.Type(), 0, v.Len()-1)
+		shortened = reflect.AppendSlice(shortened, v.Slice(0, index))
+		shortened = reflect.AppendSlice(shortened, v.Slice(index+1, v.Len()))
+		return shortened.Interface(), nil
+	}
+	return nil, ErrNotObjOrArray
+}
+
+//------------------------------------------------------------------------------
+
+// reflectValueOf converts a value so that it can be stored within a typed Go
+// value of type t. Numbers may be converted between numeric types as long as
+// the converted value is exactly equal to the original, and therefore no
+// precision is lost and the sign and magnitude are preserved.
+func reflectValueOf(value interface{}, t reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("cannot set null value as type %v", t)
+	}
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+	if reflectIsNumber(v.Kind()) && reflectIsNumber(t.Kind()) {
+		converted := v.Convert(t)
+		if r := reflectRat(v); r != nil {
+			if c := reflectRat(converted); c != nil && r.Cmp(c) == 0 {
+				return converted, nil
+			}
+		} else if k := t.Kind(); math.IsInf(v.Float(), 0) && (k == reflect.Float32 || k == reflect.Float64) {
+			return converted, nil
+		}
+		return reflect.Value{}, fmt.Errorf("cannot set value %v as type %v without losing precision", value, t)
+	}
+	return reflect.Value{}, fmt.Errorf("cannot set value of type %T as type %v", value, t)
+}
+
+// reflectRat returns the exact value of a number, or nil if it is a non-finite
+// float.
+func reflectRat(v reflect.Value) *big.Rat {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Rat).SetUint64(v.Uint())
+	}
+	f := v.Float()
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil
+	}
+	return new(big.Rat).SetFloat64(f)
+}
+
+func reflectIsNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// reflectNewObject creates an empty object for a missing element of type t
+// whilst constructing a path.
+func reflectNewObject(t reflect.Type) (reflect.Value, error) {
+	if genericObjType.AssignableTo(t) {
+		return reflect.ValueOf(map[string]interface{}{}), nil
+	}
+	switch t.Kind() {
+	case reflect.Map:
+		return reflect.MakeMap(t), nil
+	case reflect.Ptr:
+		if t.Elem().Kind() == reflect.Struct {
+			return reflect.New(t.Elem()), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("unable to construct missing element of type %v", t)
+}
+
+// reflectSet sets a value within a typed Go value by following a hierarchy of
+// path segments, where offset is the position of the first segment within the
+// full hierarchy and is used for error messages. Struct fields and the
+// elements of arrays can only be set when they are reached through a pointer,
+// and the elements of slices can be appended to with the segment '-' when the
+// slice itself is settable. Returns the value that was set.
+func reflectSet(object interface{}, offset int, value interface{}, hierarchy ...string) (interface{}, error) {
+	v := reflect.ValueOf(object)
+	for i, pathSeg := range hierarchy {
+		target := offset + i
+		last := i == len(hierarchy)-1
+
+		if v = reflectIndirect(v); !v.IsValid() {
+			return nil, fmt.Errorf("failed to resolve path segment '%v': field '%v' was not found", target, pathSeg)
+		}
+		if v.Type() == genericObjType {
+			res, err := Wrap(v.Interface()).Set(value, hierarchy[i:]...)
+			if err != nil {
+				return nil, err
+			}
+			return res.Data(), nil
+		}
+
+		switch v.Kind() {
+		case reflect.Map:
+			key, err := reflectMapKey(v.Type(), pathSeg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve path segment '%v': %v", target, err)
+			}
+			if v.IsNil() {
+				return nil, fmt.Errorf("failed to resolve path segment '%v': found nil map", target)
+			}
+			if last {
+				elem, err := reflectValueOf(value, v.Type().Elem())
+				if err != nil {
+					return nil, fmt.Errorf("failed to set path segment '%v': %v", target, err)
+				}
+				v.SetMapIndex(key, elem)
+				return value, nil
+			}
+			elem := v.MapIndex(key)
+			if !elem.IsValid() || !reflectIndirect(elem).IsValid() {
+				if elem, err = reflectNewObject(v.Type().Elem()); err != nil {
+					return nil, fmt.Errorf("failed to resolve path segment '%v': %v", target, err)
+				}
+				v.SetMapIndex(key, elem)
+			}
+			v = elem
+		case reflect.Slice, reflect.Array:
+			var elem reflect.Value
+			if pathSeg == "-" && v.Kind() == reflect.Slice {
+				if !v.CanSet() {
+					return nil, fmt.Errorf("failed to resolve path segment '%v': unable to append to slice of type %v that is not addressable", target, v.Type())
+				}
+				v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+				elem = v.Index(v.Len() - 1)
+				if !last && elem.Kind() != reflect.Struct {
+					newObj, err := reflectNewObject(elem.Type())
+					if err != nil {
+						return nil, fmt.Errorf("failed to resolve path segment '%v': %v", target, err)
+					}
+					elem.Set(newObj)
+				}
+			} else {
+				index, err := reflectIndex(v, pathSeg)
+				if err != nil {
+					return nil, fmt.Errorf("failed to resolve path segment '%v': %v", target, err)
+				}
+				elem = v.Index(index)
+			}
+			if last {
+				if !elem.CanSet() {
+					return nil, fmt.Errorf("failed to set path segment '%v': array of type %v is not addressable", target, v.Type())
+				}
+				newVal, err := reflectValueOf(value, elem.Type())
+				if err != nil {
+					return nil, fmt.Errorf("failed to set path segment '%v': %v", target, err)
+				}
+				elem.Set(newVal)
+				return value, nil
+			}
+			v = elem
+		case reflect.Struct:
+			field, ok := reflectStructField(v, pathSeg)
+			if !ok || !field.CanInterface() {
+				return nil, fmt.Errorf("failed to resolve path segment '%v': field '%v' was not found", target, pathSeg)
+			}
+			if last {
+				if !field.CanSet() {
+					return nil, fmt.Errorf("failed to set path segment '%v': struct of type %v is not addressable", target, v.Type())
+				}
+				newVal, err := reflectValueOf(value, field.Type())
+				if err != nil {
+					return nil, fmt.Errorf("failed to set path segment '%v': %v", target, err)
+				}
+				field.Set(newVal)
+				return value, nil
+			}
+			v = field
+		default:
+			return nil, ErrPathCollision
+		}
+	}
+	return nil, errors.New("failed to resolve path, hierarchy is empty")
+}
+
+//------------------------------------------------------------------------------
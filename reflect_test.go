@@ -0,0 +1,363 @@
+package gabs
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+type testReflectInner struct {
+	Value int               `json:"value"`
+	Tags  []string          `json:"tags,omitempty"`
+	Extra map[string]string `json:"extra"`
+	skip  string
+}
+
+type testReflectBase struct {
+	ID string `json:"id"`
+}
+
+type testReflectOuter struct {
+	testReflectBase
+	Name     string                 `json:"name"`
+	Inner    testReflectInner       `json:"inner"`
+	InnerPtr *testReflectInner      `json:"inner_ptr"`
+	Items    []testReflectInner     `json:"items"`
+	Generic  map[string]interface{} `json:"generic"`
+	Ignored  string                 `json:"-"`
+	NoTag    bool
+}
+
+func TestReflectSearch(t *testing.T) {
+	outer := &testReflectOuter{
+		testReflectBase: testReflectBase{ID: "abc"},
+		Name:            "foo",
+		Inner:           testReflectInner{Value: 1, Tags: []string{"a", "b"}},
+		Items:           []testReflectInner{{Value: 2}, {Value: 3}},
+		Generic:         map[string]interface{}{"nested": []interface{}{"x"}},
+		Ignored:         "nope",
+		NoTag:           true,
+	}
+
+	type testCase struct {
+		root   interface{}
+		path   string
+		output string
+	}
+	tests := []testCase{
+		{root: map[string]string{"a": "b"}, path: "a", output: `"b"`},
+		{root: map[string]string{"a": "b"}, path: "c", output: ``},
+		{root: []string{"a", "b"}, path: "1", output: `"b"`},
+		{root: []string{"a", "b"}, path: "2", output: ``},
+		{root: []map[string]interface{}{{"a": 1}}, path: "0.a", output: `1`},
+		{root: map[int][2]string{10: {"x", "y"}}, path: "10.1", output: `"y"`},
+		{root: outer, path: "name", output: `"foo"`},
+		{root: outer, path: "id", output: `"abc"`},
+		{root: outer, path: "NoTag", output: `true`},
+		{root: outer, path: "notag", output: `true`},
+		{root: outer, path: "Ignored", output: ``},
+		{root: outer, path: "inner.skip", output: ``},
+		{root: outer, path: "inner.tags.1", output: `"b"`},
+		{root: outer, path: "inner_ptr.value", output: ``},
+		{root: outer, path: "items.1.value", output: `3`},
+		{root: outer, path: "items.*.value", output: `[2,3]`},
+		{root: outer, path: "generic.nested.0", output: `"x"`},
+		{root: *outer, path: "inner.value", output: `1`},
+	}
+
+	for i, test := range tests {
+		res := Wrap(test.root).Path(test.path)
+		if test.output == "" {
+			if res != nil {
+				t.Errorf("[%d] Expected nil result, received: %v", i, res)
+			}
+			continue
+		}
+		if res == nil {
+			t.Errorf("[%d] Expected result, received nil", i)
+			continue
+		}
+		if exp, act := test.output, res.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestReflectSet(t *testing.T) {
+	outer := &testReflectOuter{
+		Items:   []testReflectInner{{Value: 2}},
+		Generic: map[string]interface{}{},
+	}
+	c := Wrap(outer)
+
+	type testCase struct {
+		value interface{}
+		path  string
+		err   bool
+	}
+	tests := []testCase{
+		{value: "bar", path: "name"},
+		{value: "xyz", path: "id"},
+		{value: 10.0, path: "inner.value"},
+		{value: 10.5, path: "inner.value", err: true},
+		{value: "10", path: "inner.value", err: true},
+		{value: "v", path: "inner.extra.k", err: true},
+		{value: 5, path: "items.0.value"},
+		{value: 6, path: "items.-.value"},
+		{value: 7, path: "items.5.value", err: true},
+		{value: "n", path: "generic.a.b"},
+		{value: 1, path: "inner_ptr.value", err: true},
+		{value: &testReflectInner{}, path: "inner_ptr"},
+		{value: 8, path: "inner_ptr.value"},
+		{value: []string{"t"}, path: "inner_ptr.tags"},
+		{value: "u", path: "inner_ptr.tags.0"},
+		{value: true, path: "missing", err: true},
+		{value: true, path: "name.deeper", err: true},
+	}
+
+	for i, test := range tests {
+		_, err := c.SetP(test.value, test.path)
+		if test.err && err == nil {
+			t.Errorf("[%d] Expected error", i)
+		} else if !test.err && err != nil {
+			t.Errorf("[%d] Unexpected error: %v", i, err)
+		}
+	}
+
+	if exp, act := "bar", outer.Name; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := "xyz", outer.ID; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := 10, outer.Inner.Value; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := 2, len(outer.Items); exp != act {
+		t.Fatalf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := 5, outer.Items[0].Value; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := 6, outer.Items[1].Value; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := `{"a":{"b":"n"}}`, Wrap(outer.Generic).String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := 8, outer.InnerPtr.Value; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := "u", outer.InnerPtr.Tags[0]; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func TestReflectSetMaps(t *testing.T) {
+	typed := map[string]map[string]int{}
+	c := Wrap(typed)
+
+	if _, err := c.SetP(1, "a.b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SetP(2, "a.c"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SetP("x", "a.d"); err == nil {
+		t.Error("Expected error from mismatched type")
+	}
+	if exp, act := `{"a":{"b":1,"c":2}}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	structs := map[string]testReflectInner{"a": {Value: 1}}
+	if _, err := Wrap(structs).SetP(2, "a.value"); err == nil {
+		t.Error("Expected error from unaddressable struct")
+	}
+	if _, err := Wrap(structs).SetP(testReflectInner{Value: 3}, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := 3, structs["a"].Value; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	slice := []string{"a", "b"}
+	if _, err := Wrap(slice).SetP("c", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := "c", slice[1]; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if _, err := Wrap([2]string{}).SetP("c", "1"); err == nil {
+		t.Error("Expected error from unaddressable array")
+	}
+}
+
+func TestReflectSetNumbers(t *testing.T) {
+	type numbers struct {
+		I   int     `json:"i"`
+		I8  int8    `json:"i8"`
+		U   uint    `json:"u"`
+		U64 uint64  `json:"u64"`
+		F32 float32 `json:"f32"`
+	}
+
+	type testCase struct {
+		value interface{}
+		path  string
+		err   bool
+	}
+	tests := []testCase{
+		{value: -1, path: "u", err: true},
+		{value: int8(-1), path: "u64", err: true},
+		{value: -1.0, path: "u", err: true},
+		{value: uint64(math.MaxUint64), path: "i", err: true},
+		{value: uint64(math.MaxUint64), path: "u64"},
+		{value: uint64(math.MaxInt64) + 1, path: "i", err: true},
+		{value: 128, path: "i8", err: true},
+		{value: -128, path: "i8"},
+		{value: 1e300, path: "i", err: true},
+		{value: 0.1, path: "f32", err: true},
+		{value: 0.5, path: "f32"},
+		{value: math.Inf(1), path: "f32"},
+		{value: math.NaN(), path: "f32", err: true},
+		{value: 3.0, path: "u"},
+	}
+
+	n := &numbers{}
+	c := Wrap(n)
+	for i, test := range tests {
+		if _, err := c.SetP(test.value, test.path); test.err && err == nil {
+			t.Errorf("[%d] Expected error, result: %+v", i, *n)
+		} else if !test.err && err != nil {
+			t.Errorf("[%d] Unexpected error: %v", i, err)
+		}
+	}
+	if exp, act := (numbers{I8: -128, U: 3, U64: math.MaxUint64, F32: float32(math.Inf(1))}), *n; exp != act {
+		t.Errorf("Wrong result: %+v != %+v", act, exp)
+	}
+
+	m := map[string]uint{}
+	if _, err := Wrap(m).Set(-1, "n"); err == nil {
+		t.Errorf("Expected error, result: %v", m["n"])
+	}
+	if _, err := Wrap(map[string]int{}).Set(uint64(math.MaxUint64), "n"); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestReflectTypedMethods(t *testing.T) {
+	slice := Wrap([]string{"a", "b"})
+	if exp, act := 2, len(slice.Children()); exp != act {
+		t.Errorf("Wrong count: %v != %v", act, exp)
+	}
+	if exp, act := "b", slice.Index(1).Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if slice.Index(2) != nil || slice.Index(-1) != nil {
+		t.Error("Expected nil out of bounds")
+	}
+	if n, err := slice.ArrayCount(); err != nil || n != 2 {
+		t.Errorf("Wrong count: %v, %v", n, err)
+	}
+	if e, err := slice.ArrayElement(0); err != nil || e.Data() != "a" {
+		t.Errorf("Wrong element: %v, %v", e, err)
+	}
+	if err := slice.Delete("0"); err == nil {
+		t.Error("Expected error from deleting array index at root")
+	}
+
+	typedMap := map[string]string{"x": "1", "y": "2"}
+	m := Wrap(typedMap)
+	if exp, act := 2, len(m.Children()); exp != act {
+		t.Errorf("Wrong count: %v != %v", act, exp)
+	}
+	if exp, act := "2", m.ChildrenMap()["y"].Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if err := m.DeleteP("x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.DeleteP("x"); err != ErrNotFound {
+		t.Errorf("Wrong error: %v", err)
+	}
+	if exp, act := 1, len(typedMap); exp != act {
+		t.Errorf("Wrong count: %v != %v", act, exp)
+	}
+
+	outer := &testReflectOuter{
+		testReflectBase: testReflectBase{ID: "foo"},
+		Inner:           testReflectInner{Tags: []string{"a"}},
+	}
+	c := Wrap(outer)
+	if err := c.ArrayAppendP("b", "inner.tags"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ArrayAppendP(1, "inner.tags"); err == nil {
+		t.Error("Expected error from mismatched element type")
+	}
+	if err := c.DeleteP("inner.tags.0"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `["b"]`, Wrap(outer.Inner.Tags).String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if err := c.DeleteP("name"); err != ErrNotObjOrArray {
+		t.Errorf("Wrong error: %v", err)
+	}
+	children := c.ChildrenMap()
+	for _, k := range []string{"id", "name", "inner", "inner_ptr", "items", "generic", "NoTag"} {
+		if _, exists := children[k]; !exists {
+			t.Errorf("Missing child: %v", k)
+		}
+	}
+	if exp, act := 7, len(children); exp != act {
+		t.Errorf("Wrong count: %v != %v", act, exp)
+	}
+	if exp, act := "foo", children["id"].Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	generic := Wrap(map[string]interface{}{"tags": []string{"a"}})
+	if err := generic.ArrayAppendP(1.0, "tags"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"tags":["a",1]}`, generic.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func TestReflectFlatten(t *testing.T) {
+	ts := time.Unix(0, 0).UTC()
+	c := Wrap(map[string]interface{}{
+		"tags":  []string{"a", "b"},
+		"m":     map[string]int{"x": 1},
+		"inner": testReflectInner{Value: 2},
+		"ts":    ts,
+		"bin":   []byte("hi"),
+	})
+	flat, err := c.Flatten()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{
+		"tags.0":      "a",
+		"tags.1":      "b",
+		"m.x":         1,
+		"inner.value": 2,
+		"ts":          ts,
+		"bin":         []byte("hi"),
+	}
+	if len(flat) != len(exp) {
+		t.Errorf("Wrong result: %v != %v", flat, exp)
+	}
+	for k, v := range exp {
+		if !Equal(Wrap(v), Wrap(flat[k])) {
+			t.Errorf("Wrong result at %v: %v != %v", k, flat[k], v)
+		}
+	}
+
+	if flat, err = Wrap([]string{"a"}).Flatten(); err != nil || flat["0"] != "a" {
+		t.Errorf("Wrong result: %v, %v", flat, err)
+	}
+}
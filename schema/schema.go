@@ -0,0 +1,495 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package schema compiles JSON Schema documents held within gabs containers
+// and validates containers against them.
+//
+// Schemas of draft 2020-12 and draft 7 are supported, where the draft is
+// selected by the $schema keyword of the root schema and defaults to 2020-12.
+// References are resolved within the schema document only, including $id and
+// $anchor based references, and remote references result in a compilation
+// error. The $dynamicRef and $recursiveRef keywords are resolved in the same
+// way as $ref.
+//
+// Regular expressions of the pattern and patternProperties keywords are
+// compiled with the regexp package, and therefore follow RE2 syntax rather
+// than ECMA-262.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/Jeffail/gabs/v2"
+)
+
+//------------------------------------------------------------------------------
+
+// Draft is a version of the JSON Schema specification.
+type Draft int
+
+// Supported drafts of the JSON Schema specification.
+const (
+	Draft2020_12 Draft = iota
+	Draft7
+)
+
+var draftURIs = map[string]Draft{
+	"https://json-schema.org/draft/2020-12/schema": Draft2020_12,
+	"http://json-schema.org/draft-07/schema":       Draft7,
+}
+
+// CompileOpt is a functional option for the Compile function.
+type CompileOpt func(o *compileOpts)
+
+type compileOpts struct {
+	draft        Draft
+	assertFormat bool
+}
+
+// CompileOptDraft sets the draft used for schemas that do not declare one
+// with the $schema keyword, which defaults to Draft2020_12.
+func CompileOptDraft(draft Draft) CompileOpt {
+	return func(o *compileOpts) {
+		o.draft = draft
+	}
+}
+
+// CompileOptAssertFormat causes the format keyword to be validated rather than
+// treated as an annotation. The formats date-time, date, time, email,
+// hostname, ipv4, ipv6, uri, uri-reference, uuid and regex are supported, and
+// other formats are ignored.
+func CompileOptAssertFormat() CompileOpt {
+	return func(o *compileOpts) {
+		o.assertFormat = true
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// ValidationError describes a single violation of a schema.
+type ValidationError struct {
+	// InstancePath is a JSON pointer to the value that violated the schema.
+	InstancePath string
+
+	// KeywordLocation is a JSON pointer to the keyword within the schema that
+	// was violated, following the path taken through any references.
+	KeywordLocation string
+
+	// Message describes the violation.
+	Message string
+}
+
+// Error returns a description of the violation and its locations.
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("instance '%v' failed schema keyword '%v': %v", e.InstancePath, e.KeywordLocation, e.Message)
+}
+
+//------------------------------------------------------------------------------
+
+// Schema is a compiled JSON Schema, which is safe for concurrent use.
+type Schema struct {
+	root         interface{}
+	draft        Draft
+	assertFormat bool
+
+	// resources maps the absolute URI of each schema resource, without a
+	// fragment, to its schema. The root schema is also registered under the
+	// empty URI.
+	resources map[string]interface{}
+
+	// anchors maps the absolute URI of each anchor to its schema.
+	anchors map[string]interface{}
+
+	patterns map[string]*regexp.Regexp
+}
+
+// Compile a JSON Schema held within a container. Returns an error if the
+// schema is malformed, contains an invalid regular expression, contains a
+// reference that cannot be resolved, or contains references that would apply
+// a schema to the same value in an infinite cycle.
+func Compile(c *gabs.Container, opts ...CompileOpt) (*Schema, error) {
+	o := compileOpts{draft: Draft2020_12}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := &Schema{
+		root:         c.Data(),
+		draft:        o.draft,
+		assertFormat: o.assertFormat,
+		resources:    map[string]interface{}{},
+		anchors:      map[string]interface{}{},
+		patterns:     map[string]*regexp.Regexp{},
+	}
+	if obj, ok := s.root.(map[string]interface{}); ok {
+		if uri, ok := obj["$schema"].(string); ok {
+			draft, known := draftURIs[strings.TrimSuffix(uri, "#")]
+			if !known {
+				return nil, fmt.Errorf("unsupported $schema '%v'", uri)
+			}
+			s.draft = draft
+		}
+	}
+
+	var st compileState
+	s.resources[""] = s.root
+	if err := s.walk(s.root, "", "", &st); err != nil {
+		return nil, err
+	}
+	for _, r := range st.refs {
+		if _, _, err := s.resolve(r.base, r.ref); err != nil {
+			return nil, fmt.Errorf("schema '%v': %w", r.location, err)
+		}
+	}
+	if err := s.checkCycles(st.nodes); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+type compileRef struct {
+	base     string
+	ref      string
+	location string
+}
+
+type compileNode struct {
+	obj      map[string]interface{}
+	base     string
+	location string
+}
+
+type compileState struct {
+	refs  []compileRef
+	nodes []compileNode
+}
+
+var (
+	singleSchemaKeywords = []string{
+		"additionalProperties", "propertyNames", "additionalItems", "contains",
+		"not", "if", "then", "else", "unevaluatedProperties", "unevaluatedItems",
+	}
+	schemaArrayKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+	schemaMapKeywords   = []string{
+		"properties", "patternProperties", "$defs", "definitions",
+		"dependentSchemas", "dependencies",
+	}
+)
+
+// walk visits every subschema of a schema in order to register resources and
+// anchors, compile regular expressions and collect references.
+func (s *Schema) walk(schema interface{}, base, location string, st *compileState) error {
+	if _, ok := schema.(bool); ok {
+		return nil
+	}
+	obj, ok := schema.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("schema '%v': expected an object or boolean, got %v", location, typeName(schema))
+	}
+
+	if id, ok := obj["$id"].(string); ok {
+		if s.draft == Draft7 && strings.HasPrefix(id, "#") {
+			s.anchors[base+id] = obj
+		} else {
+			resolved, err := resolveURI(base, id)
+			if err != nil {
+				return fmt.Errorf("schema '%v': %w", location, err)
+			}
+			base = strings.SplitN(resolved, "#", 2)[0]
+			s.resources[base] = obj
+		}
+	}
+	st.nodes = append(st.nodes, compileNode{obj: obj, base: base, location: location})
+	if anchor, ok := obj["$anchor"].(string); ok {
+		s.anchors[base+"#"+anchor] = obj
+	}
+	if anchor, ok := obj["$dynamicAnchor"].(string); ok {
+		s.anchors[base+"#"+anchor] = obj
+	}
+	for _, kw := range []string{"$ref", "$dynamicRef", "$recursiveRef"} {
+		if ref, ok := obj[kw].(string); ok {
+			st.refs = append(st.refs, compileRef{base: base, ref: ref, location: location + "/" + kw})
+		}
+	}
+
+	if p, ok := obj["pattern"].(string); ok {
+		if err := s.compilePattern(p); err != nil {
+			return fmt.Errorf("schema '%v/pattern': %w", location, err)
+		}
+	}
+	if props, ok := obj["patternProperties"].(map[string]interface{}); ok {
+		for p := range props {
+			if err := s.compilePattern(p); err != nil {
+				return fmt.Errorf("schema '%v/patternProperties': %w", location, err)
+			}
+		}
+	}
+
+	for _, kw := range singleSchemaKeywords {
+		if sub, exists := obj[kw]; exists {
+			if err := s.walk(sub, base, location+"/"+kw, st); err != nil {
+				return err
+			}
+		}
+	}
+	if items, exists := obj["items"]; exists {
+		if arr, isArray := items.([]interface{}); isArray {
+			if s.draft != Draft7 {
+				return fmt.Errorf("schema '%v/items': expected an object or boolean, got array", location)
+			}
+			for i, sub := range arr {
+				if err := s.walk(sub, base, fmt.Sprintf("%v/items/%v", location, i), st); err != nil {
+					return err
+				}
+			}
+		} else if err := s.walk(items, base, location+"/items", st); err != nil {
+			return err
+		}
+	}
+	for _, kw := range schemaArrayKeywords {
+		if subs, exists := obj[kw]; exists {
+			arr, isArray := subs.([]interface{})
+			if !isArray {
+				return fmt.Errorf("schema '%v/%v': expected an array, got %v", location, kw, typeName(subs))
+			}
+			for i, sub := range arr {
+				if err := s.walk(sub, base, fmt.Sprintf("%v/%v/%v", location, kw, i), st); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, kw := range schemaMapKeywords {
+		if subs, exists := obj[kw]; exists {
+			m, isMap := subs.(map[string]interface{})
+			if !isMap {
+				return fmt.Errorf("schema '%v/%v': expected an object, got %v", location, kw, typeName(subs))
+			}
+			for k, sub := range m {
+				if _, isArray := sub.([]interface{}); isArray && kw == "dependencies" {
+					continue
+				}
+				if err := s.walk(sub, base, location+"/"+kw+"/"+escapePointer(k), st); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// inPlaceSubschemas returns the subschemas of a schema that are applied to the
+// same instance as the schema itself, including the targets of references,
+// along with their base URIs and keyword locations.
+func (s *Schema) inPlaceSubschemas(n compileNode) []compileNode {
+	var subs []compileNode
+	add := func(v interface{}, base, location string) {
+		if obj, ok := v.(map[string]interface{}); ok {
+			if id, ok := obj["$id"].(string); ok && !(s.draft == Draft7 && strings.HasPrefix(id, "#")) {
+				if resolved, err := resolveURI(base, id); err == nil {
+					base = strings.SplitN(resolved, "#", 2)[0]
+				}
+			}
+			subs = append(subs, compileNode{obj: obj, base: base, location: location})
+		}
+	}
+
+	for _, kw := range []string{"$ref", "$dynamicRef", "$recursiveRef"} {
+		if ref, ok := n.obj[kw].(string); ok {
+			if target, targetBase, err := s.resolve(n.base, ref); err == nil {
+				add(target, targetBase, n.location+"/"+kw)
+			}
+			if s.draft == Draft7 {
+				return subs
+			}
+		}
+	}
+	for _, kw := range []string{"allOf", "anyOf", "oneOf"} {
+		arr, _ := n.obj[kw].([]interface{})
+		for i, sub := range arr {
+			add(sub, n.base, fmt.Sprintf("%v/%v/%v", n.location, kw, i))
+		}
+	}
+	for _, kw := range []string{"not", "if", "then", "else"} {
+		add(n.obj[kw], n.base, n.location+"/"+kw)
+	}
+	for _, kw := range []string{"dependentSchemas", "dependencies"} {
+		m, _ := n.obj[kw].(map[string]interface{})
+		for k, sub := range m {
+			add(sub, n.base, n.location+"/"+kw+"/"+escapePointer(k))
+		}
+	}
+	return subs
+}
+
+// checkCycles returns an error if any schema can be applied to an instance
+// again whilst validating that same instance, such as a reference to itself,
+// as validation would never terminate.
+func (s *Schema) checkCycles(nodes []compileNode) error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[uintptr]int{}
+	var visit func(n compileNode) error
+	visit = func(n compileNode) error {
+		id := reflect.ValueOf(n.obj).Pointer()
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("schema '%v': infinite reference cycle detected", n.location)
+		case visited:
+			return nil
+		}
+		state[id] = visiting
+		for _, sub := range s.inPlaceSubschemas(n) {
+			if err := visit(sub); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		return nil
+	}
+	for _, n := range nodes {
+		if err := visit(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Schema) compilePattern(p string) error {
+	if _, exists := s.patterns[p]; exists {
+		return nil
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return err
+	}
+	s.patterns[p] = re
+	return nil
+}
+
+// pattern returns a compiled regular expression, which is normally cached
+// during compilation. Patterns within schemas that were only reachable by a
+// reference to an unknown keyword are compiled on demand.
+func (s *Schema) pattern(p string) (*regexp.Regexp, error) {
+	if re, exists := s.patterns[p]; exists {
+		return re, nil
+	}
+	return regexp.Compile(p)
+}
+
+//------------------------------------------------------------------------------
+
+func resolveURI(base, ref string) (string, error) {
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid reference '%v': %w", ref, err)
+	}
+	if base == "" {
+		return refURL.String(), nil
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URI '%v': %w", base, err)
+	}
+	return baseURL.ResolveReference(refURL).String(), nil
+}
+
+// resolve finds the schema referenced by ref relative to a base URI, and
+// returns it along with its own base URI.
+func (s *Schema) resolve(base, ref string) (interface{}, string, error) {
+	resolved, err := resolveURI(base, ref)
+	if err != nil {
+		return nil, "", err
+	}
+	doc, fragment := resolved, ""
+	if idx := strings.Index(resolved, "#"); idx >= 0 {
+		doc, fragment = resolved[:idx], resolved[idx+1:]
+	}
+
+	root, exists := s.resources[doc]
+	if !exists {
+		return nil, "", fmt.Errorf("unable to resolve reference '%v': remote references are not supported", ref)
+	}
+	if fragment == "" {
+		return root, doc, nil
+	}
+	if !strings.HasPrefix(fragment, "/") {
+		target, exists := s.anchors[doc+"#"+fragment]
+		if !exists {
+			return nil, "", fmt.Errorf("unable to resolve reference '%v': anchor not found", ref)
+		}
+		return target, doc, nil
+	}
+
+	pointer, err := url.PathUnescape(fragment)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to resolve reference '%v': %w", ref, err)
+	}
+	target, err := gabs.Wrap(root).JSONPointer(pointer)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to resolve reference '%v': %w", ref, err)
+	}
+	return target.Data(), doc, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Validate a container against the schema, returning every violation found,
+// or nil if the container is valid.
+func (s *Schema) Validate(c *gabs.Container) []ValidationError {
+	errs, _ := s.validate(s.root, "", c.Data(), "", "")
+	return errs
+}
+
+// Valid returns true if a container is valid against the schema.
+func (s *Schema) Valid(c *gabs.Container) bool {
+	return len(s.Validate(c)) == 0
+}
+
+// ErrInvalid is returned by ValidateErr when a container is not valid against
+// a schema.
+var ErrInvalid = errors.New("document does not match schema")
+
+// ValidateErr validates a container against the schema and returns an error
+// wrapping ErrInvalid that describes the first violation found, or nil if the
+// container is valid.
+func (s *Schema) ValidateErr(c *gabs.Container) error {
+	errs := s.Validate(c)
+	if len(errs) == 0 {
+		return nil
+	}
+	if len(errs) == 1 {
+		return fmt.Errorf("%w: %v", ErrInvalid, errs[0])
+	}
+	return fmt.Errorf("%w: %v (and %v more)", ErrInvalid, errs[0], len(errs)-1)
+}
+
+func escapePointer(seg string) string {
+	return strings.Replace(strings.Replace(seg, "~", "~0", -1), "/", "~1", -1)
+}
+
+//------------------------------------------------------------------------------
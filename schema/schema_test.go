@@ -0,0 +1,355 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Jeffail/gabs/v2"
+)
+
+func mustParse(t *testing.T, s string) *gabs.Container {
+	t.Helper()
+	c, err := gabs.ParseJSON([]byte(s))
+	if err != nil {
+		t.Fatalf("Failed to parse '%v': %v", s, err)
+	}
+	return c
+}
+
+func mustCompile(t *testing.T, s string, opts ...CompileOpt) *Schema {
+	t.Helper()
+	sch, err := Compile(mustParse(t, s), opts...)
+	if err != nil {
+		t.Fatalf("Failed to compile '%v': %v", s, err)
+	}
+	return sch
+}
+
+type validCase struct {
+	schema string
+	opts   []CompileOpt
+	input  string
+	valid  bool
+}
+
+func runValidCases(t *testing.T, tests []validCase) {
+	t.Helper()
+	for i, test := range tests {
+		sch := mustCompile(t, test.schema, test.opts...)
+		errs := sch.Validate(mustParse(t, test.input))
+		if exp, act := test.valid, len(errs) == 0; exp != act {
+			t.Errorf("[%d] Wrong result for %v against %v: %v != %v: %v", i, test.input, test.schema, act, exp, errs)
+		}
+	}
+}
+
+func TestValidateKeywords(t *testing.T) {
+	runValidCases(t, []validCase{
+		{schema: `true`, input: `{"a":1}`, valid: true},
+		{schema: `false`, input: `{"a":1}`, valid: false},
+		{schema: `{"type":"string"}`, input: `"a"`, valid: true},
+		{schema: `{"type":"string"}`, input: `1`, valid: false},
+		{schema: `{"type":"integer"}`, input: `1.0`, valid: true},
+		{schema: `{"type":"integer"}`, input: `1.5`, valid: false},
+		{schema: `{"type":["null","boolean"]}`, input: `null`, valid: true},
+		{schema: `{"type":["null","boolean"]}`, input: `{}`, valid: false},
+		{schema: `{"enum":[1,"a",{"b":[true]}]}`, input: `{"b":[true]}`, valid: true},
+		{schema: `{"enum":[1,"a"]}`, input: `"b"`, valid: false},
+		{schema: `{"const":1}`, input: `1.0`, valid: true},
+		{schema: `{"const":false}`, input: `0`, valid: false},
+		{schema: `{"multipleOf":0.1}`, input: `0.3`, valid: true},
+		{schema: `{"multipleOf":2}`, input: `7`, valid: false},
+		{schema: `{"maximum":3,"minimum":1}`, input: `3`, valid: true},
+		{schema: `{"exclusiveMaximum":3}`, input: `3`, valid: false},
+		{schema: `{"exclusiveMinimum":1}`, input: `1.01`, valid: true},
+		{schema: `{"minimum":1}`, input: `"0"`, valid: true},
+		{schema: `{"maxLength":2}`, input: `"éé"`, valid: true},
+		{schema: `{"minLength":3}`, input: `"ab"`, valid: false},
+		{schema: `{"pattern":"^a+$"}`, input: `"aaa"`, valid: true},
+		{schema: `{"pattern":"^a+$"}`, input: `"ab"`, valid: false},
+		{schema: `{"required":["a","b"]}`, input: `{"a":1}`, valid: false},
+		{schema: `{"minProperties":1,"maxProperties":1}`, input: `{"a":1}`, valid: true},
+		{schema: `{"maxProperties":1}`, input: `{"a":1,"b":2}`, valid: false},
+		{schema: `{"properties":{"a":{"type":"string"}}}`, input: `{"a":1}`, valid: false},
+		{schema: `{"properties":{"a":{"type":"string"}},"additionalProperties":false}`, input: `{"a":"x","b":1}`, valid: false},
+		{schema: `{"patternProperties":{"^x-":{"type":"string"}},"additionalProperties":false}`, input: `{"x-a":"b"}`, valid: true},
+		{schema: `{"patternProperties":{"^x-":{"type":"string"}}}`, input: `{"x-a":1}`, valid: false},
+		{schema: `{"propertyNames":{"maxLength":2}}`, input: `{"abc":1}`, valid: false},
+		{schema: `{"dependentRequired":{"a":["b"]}}`, input: `{"a":1}`, valid: false},
+		{schema: `{"dependentRequired":{"a":["b"]}}`, input: `{"b":1}`, valid: true},
+		{schema: `{"dependentSchemas":{"a":{"required":["c"]}}}`, input: `{"a":1}`, valid: false},
+		{schema: `{"minItems":1,"maxItems":2}`, input: `[1,2,3]`, valid: false},
+		{schema: `{"uniqueItems":true}`, input: `[1,{"a":1},1.0]`, valid: false},
+		{schema: `{"uniqueItems":true}`, input: `[1,"1",true]`, valid: true},
+		{schema: `{"items":{"type":"number"}}`, input: `[1,2,"3"]`, valid: false},
+		{schema: `{"prefixItems":[{"type":"string"}],"items":{"type":"number"}}`, input: `["a",1,2]`, valid: true},
+		{schema: `{"prefixItems":[{"type":"string"}],"items":false}`, input: `["a",1]`, valid: false},
+		{schema: `{"contains":{"type":"string"}}`, input: `[1,2]`, valid: false},
+		{schema: `{"contains":{"type":"string"},"minContains":2,"maxContains":3}`, input: `["a",1,"b"]`, valid: true},
+		{schema: `{"contains":{"type":"string"},"maxContains":1}`, input: `["a","b"]`, valid: false},
+		{schema: `{"allOf":[{"type":"number"},{"minimum":2}]}`, input: `1`, valid: false},
+		{schema: `{"anyOf":[{"type":"number"},{"type":"string"}]}`, input: `"a"`, valid: true},
+		{schema: `{"anyOf":[{"type":"number"},{"type":"string"}]}`, input: `null`, valid: false},
+		{schema: `{"oneOf":[{"type":"number"},{"minimum":1}]}`, input: `2`, valid: false},
+		{schema: `{"oneOf":[{"type":"number"},{"minimum":1}]}`, input: `0`, valid: true},
+		{schema: `{"not":{"type":"string"}}`, input: `"a"`, valid: false},
+		{schema: `{"if":{"properties":{"a":{"const":1}}},"then":{"required":["b"]},"else":{"required":["c"]}}`, input: `{"a":1,"b":1}`, valid: true},
+		{schema: `{"if":{"properties":{"a":{"const":1}}},"then":{"required":["b"]},"else":{"required":["c"]}}`, input: `{"a":2,"b":1}`, valid: false},
+	})
+}
+
+func TestValidateRefs(t *testing.T) {
+	runValidCases(t, []validCase{
+		{schema: `{"$defs":{"pos":{"minimum":0}},"properties":{"a":{"$ref":"#/$defs/pos"}}}`, input: `{"a":-1}`, valid: false},
+		{schema: `{"$defs":{"pos":{"minimum":0}},"properties":{"a":{"$ref":"#/$defs/pos"}}}`, input: `{"a":1}`, valid: true},
+		{schema: `{"$defs":{"a~b/c":{"type":"string"}},"$ref":"#/$defs/a~0b~1c"}`, input: `1`, valid: false},
+		{schema: `{"$defs":{"x":{"$anchor":"name","type":"string"}},"$ref":"#name"}`, input: `"a"`, valid: true},
+		{schema: `{"$id":"https://example.com/root","$defs":{"x":{"$id":"item","type":"string"}},"items":{"$ref":"item"}}`, input: `["a",1]`, valid: false},
+		{schema: `{"$defs":{"node":{"type":"object","properties":{"next":{"$ref":"#/$defs/node"}},"additionalProperties":false}},"$ref":"#/$defs/node"}`, input: `{"next":{"next":{}}}`, valid: true},
+		{schema: `{"$defs":{"node":{"type":"object","properties":{"next":{"$ref":"#/$defs/node"}},"additionalProperties":false}},"$ref":"#/$defs/node"}`, input: `{"next":{"nope":{}}}`, valid: false},
+		{schema: `{"$defs":{"a":{"type":"number"}},"$ref":"#/$defs/a","maximum":1}`, input: `2`, valid: false},
+	})
+}
+
+func TestValidateUnevaluated(t *testing.T) {
+	runValidCases(t, []validCase{
+		{schema: `{"allOf":[{"properties":{"a":true}}],"unevaluatedProperties":false}`, input: `{"a":1}`, valid: true},
+		{schema: `{"allOf":[{"properties":{"a":true}}],"unevaluatedProperties":false}`, input: `{"a":1,"b":2}`, valid: false},
+		{schema: `{"anyOf":[{"properties":{"a":true}},{"properties":{"b":true}}],"unevaluatedProperties":false}`, input: `{"a":1,"b":2}`, valid: true},
+		{schema: `{"if":{"properties":{"a":{"const":1}}},"then":{"properties":{"b":true}},"unevaluatedProperties":false}`, input: `{"a":1,"b":2}`, valid: true},
+		{schema: `{"if":{"properties":{"a":{"const":1}}},"then":{"properties":{"b":true}},"unevaluatedProperties":false}`, input: `{"a":2,"b":2}`, valid: false},
+		{schema: `{"$defs":{"base":{"properties":{"a":true}}},"$ref":"#/$defs/base","properties":{"b":true},"unevaluatedProperties":false}`, input: `{"a":1,"b":2}`, valid: true},
+		{schema: `{"unevaluatedProperties":{"type":"number"}}`, input: `{"a":"x"}`, valid: false},
+		{schema: `{"prefixItems":[true],"unevaluatedItems":false}`, input: `[1]`, valid: true},
+		{schema: `{"prefixItems":[true],"unevaluatedItems":false}`, input: `[1,2]`, valid: false},
+		{schema: `{"allOf":[{"items":true}],"unevaluatedItems":false}`, input: `[1,2]`, valid: true},
+		{schema: `{"contains":{"type":"string"},"unevaluatedItems":{"type":"number"}}`, input: `["a",1]`, valid: true},
+		{schema: `{"contains":{"type":"string"},"unevaluatedItems":{"type":"number"}}`, input: `["a",true]`, valid: false},
+	})
+}
+
+func TestValidateDraft7(t *testing.T) {
+	draft7 := `"$schema":"http://json-schema.org/draft-07/schema#",`
+	runValidCases(t, []validCase{
+		{schema: `{` + draft7 + `"items":[{"type":"string"}],"additionalItems":false}`, input: `["a"]`, valid: true},
+		{schema: `{` + draft7 + `"items":[{"type":"string"}],"additionalItems":false}`, input: `["a",1]`, valid: false},
+		{schema: `{` + draft7 + `"items":{"type":"string"}}`, input: `["a",1]`, valid: false},
+		{schema: `{` + draft7 + `"dependencies":{"a":["b"],"c":{"required":["d"]}}}`, input: `{"a":1,"b":1,"c":1}`, valid: false},
+		{schema: `{` + draft7 + `"dependencies":{"a":["b"],"c":{"required":["d"]}}}`, input: `{"a":1,"b":1,"c":1,"d":1}`, valid: true},
+		{schema: `{` + draft7 + `"definitions":{"a":{"type":"number"}},"$ref":"#/definitions/a","maximum":1}`, input: `2`, valid: true},
+		{schema: `{` + draft7 + `"definitions":{"a":{"$id":"#num","type":"number"}},"$ref":"#num"}`, input: `"x"`, valid: false},
+		{schema: `{"items":[{"type":"string"}],"additionalItems":false}`, opts: []CompileOpt{CompileOptDraft(Draft7)}, input: `["a",1]`, valid: false},
+	})
+}
+
+func TestValidateFormat(t *testing.T) {
+	assert := []CompileOpt{CompileOptAssertFormat()}
+	runValidCases(t, []validCase{
+		{schema: `{"format":"date-time"}`, input: `"nope"`, valid: true},
+		{schema: `{"format":"date-time"}`, opts: assert, input: `"nope"`, valid: false},
+		{schema: `{"format":"date-time"}`, opts: assert, input: `"2023-01-02T15:04:05.5+01:00"`, valid: true},
+		{schema: `{"format":"date"}`, opts: assert, input: `"2023-02-30"`, valid: false},
+		{schema: `{"format":"time"}`, opts: assert, input: `"15:04:05Z"`, valid: true},
+		{schema: `{"format":"email"}`, opts: assert, input: `"foo@example.com"`, valid: true},
+		{schema: `{"format":"email"}`, opts: assert, input: `"Foo <foo@example.com>"`, valid: false},
+		{schema: `{"format":"hostname"}`, opts: assert, input: `"a-b.example.com"`, valid: true},
+		{schema: `{"format":"hostname"}`, opts: assert, input: `"-a.com"`, valid: false},
+		{schema: `{"format":"ipv4"}`, opts: assert, input: `"10.0.0.1"`, valid: true},
+		{schema: `{"format":"ipv4"}`, opts: assert, input: `"::1"`, valid: false},
+		{schema: `{"format":"ipv6"}`, opts: assert, input: `"::1"`, valid: true},
+		{schema: `{"format":"uri"}`, opts: assert, input: `"/relative"`, valid: false},
+		{schema: `{"format":"uuid"}`, opts: assert, input: `"123e4567-e89b-12d3-a456-426614174000"`, valid: true},
+		{schema: `{"format":"regex"}`, opts: assert, input: `"("`, valid: false},
+		{schema: `{"format":"unknown"}`, opts: assert, input: `"x"`, valid: true},
+		{schema: `{"format":"date"}`, opts: assert, input: `10`, valid: true},
+	})
+}
+
+func TestValidationErrorLocations(t *testing.T) {
+	sch := mustCompile(t, `{
+	"$defs": {"port": {"type": "integer", "maximum": 65535}},
+	"type": "object",
+	"required": ["name"],
+	"properties": {
+		"servers": {
+			"type": "array",
+			"items": {
+				"properties": {"port": {"$ref": "#/$defs/port"}, "a/b": {"type": "string"}}
+			}
+		}
+	}
+}`)
+
+	errs := sch.Validate(mustParse(t, `{"servers":[{"port":80},{"port":70000,"a/b":1}]}`))
+
+	exp := []ValidationError{
+		{InstancePath: "", KeywordLocation: "/required", Message: "missing required property 'name'"},
+		{InstancePath: "/servers/1/a~1b", KeywordLocation: "/properties/servers/items/properties/a~1b/type", Message: "expected string, got number"},
+		{InstancePath: "/servers/1/port", KeywordLocation: "/properties/servers/items/properties/port/$ref/maximum", Message: "number exceeds the maximum of 65535"},
+	}
+	if len(errs) != len(exp) {
+		t.Fatalf("Wrong count of errors: %v != %v: %v", len(errs), len(exp), errs)
+	}
+	for i, e := range exp {
+		if act := errs[i]; act != e {
+			t.Errorf("[%d] Wrong result: %+v != %+v", i, act, e)
+		}
+	}
+
+	if err := sch.ValidateErr(mustParse(t, `{"name":"foo"}`)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	err := sch.ValidateErr(mustParse(t, `{"servers":"nope"}`))
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected ErrInvalid, received: %v", err)
+	}
+	if exp, act := "document does not match schema: instance '' failed schema keyword '/required': missing required property 'name' (and 1 more)", err.Error(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if !sch.Valid(mustParse(t, `{"name":"foo","servers":[]}`)) {
+		t.Error("Expected valid document")
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		schema string
+		err    string
+	}{
+		{schema: `"nope"`, err: "expected an object or boolean"},
+		{schema: `{"properties":{"a":1}}`, err: "schema '/properties/a'"},
+		{schema: `{"pattern":"("}`, err: "schema '/pattern'"},
+		{schema: `{"$ref":"#/$defs/missing"}`, err: "unable to resolve reference"},
+		{schema: `{"$ref":"https://example.com/other.json"}`, err: "remote references are not supported"},
+		{schema: `{"$schema":"http://json-schema.org/draft-04/schema#"}`, err: "unsupported $schema"},
+		{schema: `{"items":[true]}`, err: "schema '/items'"},
+		{schema: `{"allOf":{}}`, err: "expected an array"},
+		{schema: `{"$defs":{"a":{"$ref":"#/$defs/a"}},"$ref":"#/$defs/a"}`, err: "infinite reference cycle"},
+		{schema: `{"$defs":{"a":{"allOf":[{"$ref":"#/$defs/b"}]},"b":{"anyOf":[{"$ref":"#/$defs/a"}]}},"properties":{"x":{"$ref":"#/$defs/a"}}}`, err: "infinite reference cycle"},
+		{schema: `{"not":{"$ref":"#"}}`, err: "infinite reference cycle"},
+		{schema: `{"$id":"https://example.com/root","$defs":{"x":{"$id":"item","if":{"$ref":"https://example.com/item"}}}}`, err: "infinite reference cycle"},
+	}
+	for i, test := range tests {
+		_, err := Compile(mustParse(t, test.schema))
+		if err == nil {
+			t.Errorf("[%d] Expected error", i)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("[%d] Wrong error: %v does not contain %v", i, err, test.err)
+		}
+	}
+}
+
+func TestCompileRecursiveSchemas(t *testing.T) {
+	tests := []string{
+		`{"properties":{"child":{"$ref":"#"}}}`,
+		`{"items":{"$ref":"#"}}`,
+		`{"$schema":"http://json-schema.org/draft-07/schema#","definitions":{"a":{"$ref":"#/definitions/b","allOf":[{"$ref":"#/definitions/a"}]},"b":true},"$ref":"#/definitions/a"}`,
+	}
+	for i, test := range tests {
+		if _, err := Compile(mustParse(t, test)); err != nil {
+			t.Errorf("[%d] Unexpected error: %v", i, err)
+		}
+	}
+
+	sch := mustCompile(t, `{"type":"object","properties":{"child":{"$ref":"#"}},"additionalProperties":false}`)
+	if !sch.Valid(mustParse(t, `{"child":{"child":{}}}`)) {
+		t.Error("Expected valid document")
+	}
+	if sch.Valid(mustParse(t, `{"child":{"child":{"nope":1}}}`)) {
+		t.Error("Expected invalid document")
+	}
+}
+
+func TestValidateUseNumber(t *testing.T) {
+	sch := mustCompile(t, `{"maximum":9007199254740993,"multipleOf":0.01}`)
+	c, err := gabs.ParseJSONWithOpts([]byte(`9007199254740994`), gabs.ParseOptUseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sch.Valid(c) {
+		t.Error("Expected invalid document")
+	}
+	if c, err = gabs.ParseJSONWithOpts([]byte(`1.23`), gabs.ParseOptUseNumber()); err != nil {
+		t.Fatal(err)
+	}
+	if !sch.Valid(c) {
+		t.Errorf("Expected valid document: %v", sch.Validate(c))
+	}
+
+	for i, test := range []struct {
+		schema, input string
+	}{
+		{schema: `{"const":0.1}`, input: `0.1`},
+		{schema: `{"enum":[1.1,2.2]}`, input: `2.2`},
+		{schema: `{"const":{"a":[0.3]}}`, input: `{"a":[0.3]}`},
+		{schema: `{"minimum":0.1,"maximum":0.1,"multipleOf":0.1}`, input: `0.1`},
+	} {
+		for _, numSchema := range []bool{true, false} {
+			var schemaOpts, inputOpts []gabs.ParseOpt
+			if numSchema {
+				schemaOpts = append(schemaOpts, gabs.ParseOptUseNumber())
+			} else {
+				inputOpts = append(inputOpts, gabs.ParseOptUseNumber())
+			}
+			sc, err := gabs.ParseJSONWithOpts([]byte(test.schema), schemaOpts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			in, err := gabs.ParseJSONWithOpts([]byte(test.input), inputOpts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sch, err := Compile(sc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if errs := sch.Validate(in); len(errs) > 0 {
+				t.Errorf("[%d] Unexpected violations: %v", i, errs)
+			}
+		}
+	}
+}
+
+func TestValidateInferredSchema(t *testing.T) {
+	tests := [][]string{
+		{
+			`{"id":"123e4567-e89b-12d3-a456-426614174000","status":"ok","tags":["a"],"at":"2023-01-02T15:04:05Z","n":1}`,
+			`{"id":"123e4567-e89b-12d3-a456-426614174001","status":"ok","tags":[],"at":"2023-01-03T15:04:05Z","n":2.5}`,
+			`{"id":"123e4567-e89b-12d3-a456-426614174002","status":null,"tags":["b","c"],"at":"2023-01-04T15:04:05Z"}`,
+		},
+		{`{"v":"a"}`, `{"v":"a"}`, `{"v":"a"}`, `{"v":3}`},
+		{`{"v":"a"}`, `{"v":"a"}`, `{"v":true}`, `{"v":null}`, `{"v":["a","a",1]}`},
+		{`{"v":"2023-01-02"}`, `{"v":"2023-01-03"}`, `{"v":{"w":"2023-01-04"}}`, `{"v":-1.5}`},
+		{`["x","x","x",1,null,[2,"x"],{"a":"x"}]`, `"x"`, `7`},
+	}
+
+	for i, test := range tests {
+		samples := make([]*gabs.Container, len(test))
+		for j, s := range test {
+			samples[j] = mustParse(t, s)
+		}
+		sch, err := Compile(gabs.InferSchema(samples...), CompileOptAssertFormat())
+		if err != nil {
+			t.Fatalf("[%d] Failed to compile: %v", i, err)
+		}
+		for j, s := range samples {
+			if errs := sch.Validate(s); len(errs) > 0 {
+				t.Errorf("[%d:%d] Unexpected violations: %v", i, j, errs)
+			}
+		}
+	}
+
+	sch, err := Compile(gabs.InferSchema(
+		mustParse(t, `{"id":"123e4567-e89b-12d3-a456-426614174000"}`),
+		mustParse(t, `{"id":"123e4567-e89b-12d3-a456-426614174001"}`),
+	), CompileOptAssertFormat())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sch.Valid(mustParse(t, `{"id":"nope"}`)) {
+		t.Error("Expected invalid document")
+	}
+}
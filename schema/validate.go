@@ -0,0 +1,538 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package schema
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Jeffail/gabs/v2"
+)
+
+//------------------------------------------------------------------------------
+
+// evaluated records the properties and items of an instance that were
+// evaluated by a schema and its subschemas, which is required by the
+// unevaluatedProperties and unevaluatedItems keywords.
+type evaluated struct {
+	props    map[string]bool
+	items    map[int]bool
+	allItems bool
+}
+
+func (e *evaluated) prop(key string) {
+	if e.props == nil {
+		e.props = map[string]bool{}
+	}
+	e.props[key] = true
+}
+
+func (e *evaluated) item(index int) {
+	if e.items == nil {
+		e.items = map[int]bool{}
+	}
+	e.items[index] = true
+}
+
+func (e *evaluated) merge(other evaluated) {
+	for k := range other.props {
+		e.prop(k)
+	}
+	for i := range other.items {
+		e.item(i)
+	}
+	e.allItems = e.allItems || other.allItems
+}
+
+//------------------------------------------------------------------------------
+
+type keywordFailure struct {
+	errs []ValidationError
+}
+
+func (k *keywordFailure) add(instPath, kwPath, msg string, args ...interface{}) {
+	k.errs = append(k.errs, ValidationError{
+		InstancePath:    instPath,
+		KeywordLocation: kwPath,
+		Message:         fmt.Sprintf(msg, args...),
+	})
+}
+
+// validate an instance against a schema, returning any violations and the
+// annotations of evaluated properties and items, which are only valid when
+// there are no violations.
+func (s *Schema) validate(schema interface{}, base string, inst interface{}, instPath, kwPath string) ([]ValidationError, evaluated) {
+	var ev evaluated
+	var k keywordFailure
+
+	obj, isObj := schema.(map[string]interface{})
+	if !isObj {
+		if allow, isBool := schema.(bool); isBool && !allow {
+			k.add(instPath, kwPath, "no values are allowed by a false schema")
+		}
+		return k.errs, ev
+	}
+
+	if id, ok := obj["$id"].(string); ok && !(s.draft == Draft7 && strings.HasPrefix(id, "#")) {
+		if resolved, err := resolveURI(base, id); err == nil {
+			base = strings.SplitN(resolved, "#", 2)[0]
+		}
+	}
+
+	for _, kw := range []string{"$ref", "$dynamicRef", "$recursiveRef"} {
+		ref, ok := obj[kw].(string)
+		if !ok {
+			continue
+		}
+		target, targetBase, err := s.resolve(base, ref)
+		if err != nil {
+			k.add(instPath, kwPath+"/"+kw, "%v", err)
+			continue
+		}
+		errs, refEv := s.validate(target, targetBase, inst, instPath, kwPath+"/"+kw)
+		k.errs = append(k.errs, errs...)
+		if len(errs) == 0 {
+			ev.merge(refEv)
+		}
+		if s.draft == Draft7 {
+			// In draft 7 all other keywords are ignored alongside a $ref.
+			return k.errs, ev
+		}
+	}
+
+	s.validateGeneric(obj, inst, instPath, kwPath, &k)
+	s.validateApplicators(obj, base, inst, instPath, kwPath, &k, &ev)
+
+	switch t := inst.(type) {
+	case map[string]interface{}:
+		s.validateObject(obj, base, t, instPath, kwPath, &k, &ev)
+	case []interface{}:
+		s.validateArray(obj, base, t, instPath, kwPath, &k, &ev)
+	case string:
+		s.validateString(obj, t, instPath, kwPath, &k)
+	default:
+		if n, ok := toRat(inst); ok {
+			s.validateNumber(obj, n, instPath, kwPath, &k)
+		}
+	}
+	return k.errs, ev
+}
+
+//------------------------------------------------------------------------------
+
+func (s *Schema) validateGeneric(obj map[string]interface{}, inst interface{}, instPath, kwPath string, k *keywordFailure) {
+	if types, exists := obj["type"]; exists {
+		var allowed []string
+		switch t := types.(type) {
+		case string:
+			allowed = []string{t}
+		case []interface{}:
+			for _, v := range t {
+				if str, ok := v.(string); ok {
+					allowed = append(allowed, str)
+				}
+			}
+		}
+		matched := false
+		for _, a := range allowed {
+			if typeMatches(a, inst) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			k.add(instPath, kwPath+"/type", "expected %v, got %v", strings.Join(allowed, " or "), typeName(inst))
+		}
+	}
+
+	if enum, ok := obj["enum"].([]interface{}); ok {
+		matched := false
+		for _, v := range enum {
+			if equal(v, inst) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			k.add(instPath, kwPath+"/enum", "value is not one of the allowed values")
+		}
+	}
+
+	if c, exists := obj["const"]; exists && !equal(c, inst) {
+		k.add(instPath, kwPath+"/const", "value does not match the constant %v", gabs.Wrap(c).String())
+	}
+}
+
+func (s *Schema) validateApplicators(obj map[string]interface{}, base string, inst interface{}, instPath, kwPath string, k *keywordFailure, ev *evaluated) {
+	if subs, ok := obj["allOf"].([]interface{}); ok {
+		for i, sub := range subs {
+			errs, subEv := s.validate(sub, base, inst, instPath, fmt.Sprintf("%v/allOf/%v", kwPath, i))
+			k.errs = append(k.errs, errs...)
+			if len(errs) == 0 {
+				ev.merge(subEv)
+			}
+		}
+	}
+
+	if subs, ok := obj["anyOf"].([]interface{}); ok {
+		matched := false
+		for i, sub := range subs {
+			if errs, subEv := s.validate(sub, base, inst, instPath, fmt.Sprintf("%v/anyOf/%v", kwPath, i)); len(errs) == 0 {
+				matched = true
+				ev.merge(subEv)
+			}
+		}
+		if !matched {
+			k.add(instPath, kwPath+"/anyOf", "value does not match any of the schemas")
+		}
+	}
+
+	if subs, ok := obj["oneOf"].([]interface{}); ok {
+		var matched []int
+		var matchedEv evaluated
+		for i, sub := range subs {
+			if errs, subEv := s.validate(sub, base, inst, instPath, fmt.Sprintf("%v/oneOf/%v", kwPath, i)); len(errs) == 0 {
+				matched = append(matched, i)
+				matchedEv = subEv
+			}
+		}
+		switch len(matched) {
+		case 0:
+			k.add(instPath, kwPath+"/oneOf", "value does not match any of the schemas")
+		case 1:
+			ev.merge(matchedEv)
+		default:
+			k.add(instPath, kwPath+"/oneOf", "value matches more than one schema, matched indexes %v", matched)
+		}
+	}
+
+	if sub, exists := obj["not"]; exists {
+		if errs, _ := s.validate(sub, base, inst, instPath, kwPath+"/not"); len(errs) == 0 {
+			k.add(instPath, kwPath+"/not", "value must not match the schema")
+		}
+	}
+
+	if sub, exists := obj["if"]; exists {
+		ifErrs, ifEv := s.validate(sub, base, inst, instPath, kwPath+"/if")
+		branch := "then"
+		if len(ifErrs) == 0 {
+			ev.merge(ifEv)
+		} else {
+			branch = "else"
+		}
+		if branchSchema, exists := obj[branch]; exists {
+			errs, branchEv := s.validate(branchSchema, base, inst, instPath, kwPath+"/"+branch)
+			k.errs = append(k.errs, errs...)
+			if len(errs) == 0 {
+				ev.merge(branchEv)
+			}
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func (s *Schema) validateObject(obj map[string]interface{}, base string, inst map[string]interface{}, instPath, kwPath string, k *keywordFailure, ev *evaluated) {
+	keys := make([]string, 0, len(inst))
+	for key := range inst {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if n, ok := schemaInt(obj["maxProperties"]); ok && len(inst) > n {
+		k.add(instPath, kwPath+"/maxProperties", "object has %v properties, exceeding the maximum of %v", len(inst), n)
+	}
+	if n, ok := schemaInt(obj["minProperties"]); ok && len(inst) < n {
+		k.add(instPath, kwPath+"/minProperties", "object has %v properties, below the minimum of %v", len(inst), n)
+	}
+
+	if required, ok := obj["required"].([]interface{}); ok {
+		for _, r := range required {
+			if key, ok := r.(string); ok {
+				if _, exists := inst[key]; !exists {
+					k.add(instPath, kwPath+"/required", "missing required property '%v'", key)
+				}
+			}
+		}
+	}
+
+	dependentRequired, _ := obj["dependentRequired"].(map[string]interface{})
+	dependentSchemas, _ := obj["dependentSchemas"].(map[string]interface{})
+	dependentKw := "dependentRequired"
+	dependentSchemasKw := "dependentSchemas"
+	if s.draft == Draft7 {
+		dependentRequired, dependentSchemas = map[string]interface{}{}, map[string]interface{}{}
+		deps, _ := obj["dependencies"].(map[string]interface{})
+		for key, dep := range deps {
+			if _, isArray := dep.([]interface{}); isArray {
+				dependentRequired[key] = dep
+			} else {
+				dependentSchemas[key] = dep
+			}
+		}
+		dependentKw, dependentSchemasKw = "dependencies", "dependencies"
+	}
+	for _, key := range keys {
+		if deps, ok := dependentRequired[key].([]interface{}); ok {
+			for _, d := range deps {
+				if dep, ok := d.(string); ok {
+					if _, exists := inst[dep]; !exists {
+						k.add(instPath, kwPath+"/"+dependentKw+"/"+escapePointer(key), "property '%v' is required when property '%v' is present", dep, key)
+					}
+				}
+			}
+		}
+		if sub, exists := dependentSchemas[key]; exists {
+			errs, subEv := s.validate(sub, base, inst, instPath, kwPath+"/"+dependentSchemasKw+"/"+escapePointer(key))
+			k.errs = append(k.errs, errs...)
+			if len(errs) == 0 {
+				ev.merge(subEv)
+			}
+		}
+	}
+
+	if sub, exists := obj["propertyNames"]; exists {
+		for _, key := range keys {
+			errs, _ := s.validate(sub, base, key, instPath+"/"+escapePointer(key), kwPath+"/propertyNames")
+			k.errs = append(k.errs, errs...)
+		}
+	}
+
+	properties, _ := obj["properties"].(map[string]interface{})
+	patternProperties, _ := obj["patternProperties"].(map[string]interface{})
+	additional, hasAdditional := obj["additionalProperties"]
+	for _, key := range keys {
+		childPath := instPath + "/" + escapePointer(key)
+		matched := false
+		if sub, exists := properties[key]; exists {
+			matched = true
+			errs, _ := s.validate(sub, base, inst[key], childPath, kwPath+"/properties/"+escapePointer(key))
+			k.errs = append(k.errs, errs...)
+		}
+		for p, sub := range patternProperties {
+			re, err := s.pattern(p)
+			if err != nil || !re.MatchString(key) {
+				continue
+			}
+			matched = true
+			errs, _ := s.validate(sub, base, inst[key], childPath, kwPath+"/patternProperties/"+escapePointer(p))
+			k.errs = append(k.errs, errs...)
+		}
+		if !matched && hasAdditional {
+			matched = true
+			errs, _ := s.validate(additional, base, inst[key], childPath, kwPath+"/additionalProperties")
+			k.errs = append(k.errs, errs...)
+		}
+		if matched {
+			ev.prop(key)
+		}
+	}
+
+	if unevaluated, exists := obj["unevaluatedProperties"]; exists {
+		for _, key := range keys {
+			if ev.props[key] {
+				continue
+			}
+			errs, _ := s.validate(unevaluated, base, inst[key], instPath+"/"+escapePointer(key), kwPath+"/unevaluatedProperties")
+			k.errs = append(k.errs, errs...)
+			ev.prop(key)
+		}
+	}
+}
+
+func (s *Schema) validateArray(obj map[string]interface{}, base string, inst []interface{}, instPath, kwPath string, k *keywordFailure, ev *evaluated) {
+	if n, ok := schemaInt(obj["maxItems"]); ok && len(inst) > n {
+		k.add(instPath, kwPath+"/maxItems", "array has %v items, exceeding the maximum of %v", len(inst), n)
+	}
+	if n, ok := schemaInt(obj["minItems"]); ok && len(inst) < n {
+		k.add(instPath, kwPath+"/minItems", "array has %v items, below the minimum of %v", len(inst), n)
+	}
+	if unique, _ := obj["uniqueItems"].(bool); unique {
+	uniqueLoop:
+		for i := 0; i < len(inst); i++ {
+			for j := i + 1; j < len(inst); j++ {
+				if equal(inst[i], inst[j]) {
+					k.add(instPath, kwPath+"/uniqueItems", "items at indexes %v and %v are equal", i, j)
+					break uniqueLoop
+				}
+			}
+		}
+	}
+
+	// Resolve the keywords that apply to items by position, which differ
+	// between drafts.
+	prefixKw, restKw := "prefixItems", "items"
+	prefix, _ := obj["prefixItems"].([]interface{})
+	rest, hasRest := obj["items"]
+	if s.draft == Draft7 {
+		if arr, isArray := rest.([]interface{}); isArray {
+			prefixKw, restKw = "items", "additionalItems"
+			prefix = arr
+			rest, hasRest = obj["additionalItems"]
+		} else {
+			prefix = nil
+		}
+	}
+	for i, item := range inst {
+		childPath := instPath + "/" + strconv.Itoa(i)
+		if i < len(prefix) {
+			errs, _ := s.validate(prefix[i], base, item, childPath, fmt.Sprintf("%v/%v/%v", kwPath, prefixKw, i))
+			k.errs = append(k.errs, errs...)
+			ev.item(i)
+		} else if hasRest {
+			errs, _ := s.validate(rest, base, item, childPath, kwPath+"/"+restKw)
+			k.errs = append(k.errs, errs...)
+			ev.allItems = true
+		}
+	}
+
+	if contains, exists := obj["contains"]; exists {
+		count := 0
+		for i, item := range inst {
+			if errs, _ := s.validate(contains, base, item, instPath+"/"+strconv.Itoa(i), kwPath+"/contains"); len(errs) == 0 {
+				count++
+				ev.item(i)
+			}
+		}
+		minContains, hasMin := schemaInt(obj["minContains"])
+		if !hasMin || s.draft == Draft7 {
+			minContains = 1
+		}
+		if count < minContains {
+			if minContains == 1 {
+				k.add(instPath, kwPath+"/contains", "array does not contain a matching item")
+			} else {
+				k.add(instPath, kwPath+"/minContains", "array contains %v matching items, below the minimum of %v", count, minContains)
+			}
+		}
+		if maxContains, ok := schemaInt(obj["maxContains"]); ok && s.draft != Draft7 && count > maxContains {
+			k.add(instPath, kwPath+"/maxContains", "array contains %v matching items, exceeding the maximum of %v", count, maxContains)
+		}
+	}
+
+	if unevaluated, exists := obj["unevaluatedItems"]; exists && !ev.allItems {
+		for i, item := range inst {
+			if ev.items[i] {
+				continue
+			}
+			errs, _ := s.validate(unevaluated, base, item, instPath+"/"+strconv.Itoa(i), kwPath+"/unevaluatedItems")
+			k.errs = append(k.errs, errs...)
+		}
+		ev.allItems = true
+	}
+}
+
+func (s *Schema) validateString(obj map[string]interface{}, inst string, instPath, kwPath string, k *keywordFailure) {
+	length := utf8.RuneCountInString(inst)
+	if n, ok := schemaInt(obj["maxLength"]); ok && length > n {
+		k.add(instPath, kwPath+"/maxLength", "string has length %v, exceeding the maximum of %v", length, n)
+	}
+	if n, ok := schemaInt(obj["minLength"]); ok && length < n {
+		k.add(instPath, kwPath+"/minLength", "string has length %v, below the minimum of %v", length, n)
+	}
+	if p, ok := obj["pattern"].(string); ok {
+		if re, err := s.pattern(p); err == nil && !re.MatchString(inst) {
+			k.add(instPath, kwPath+"/pattern", "string does not match pattern '%v'", p)
+		}
+	}
+	if format, ok := obj["format"].(string); ok && s.assertFormat {
+		if check, known := formats[format]; known && !check(inst) {
+			k.add(instPath, kwPath+"/format", "string is not a valid %v", format)
+		}
+	}
+}
+
+func (s *Schema) validateNumber(obj map[string]interface{}, inst *big.Rat, instPath, kwPath string, k *keywordFailure) {
+	if m, ok := toRat(obj["multipleOf"]); ok && m.Sign() > 0 {
+		if !new(big.Rat).Quo(inst, m).IsInt() {
+			k.add(instPath, kwPath+"/multipleOf", "number is not a multiple of %v", gabs.Wrap(obj["multipleOf"]).String())
+		}
+	}
+	if m, ok := toRat(obj["maximum"]); ok && inst.Cmp(m) > 0 {
+		k.add(instPath, kwPath+"/maximum", "number exceeds the maximum of %v", gabs.Wrap(obj["maximum"]).String())
+	}
+	if m, ok := toRat(obj["exclusiveMaximum"]); ok && inst.Cmp(m) >= 0 {
+		k.add(instPath, kwPath+"/exclusiveMaximum", "number must be less than %v", gabs.Wrap(obj["exclusiveMaximum"]).String())
+	}
+	if m, ok := toRat(obj["minimum"]); ok && inst.Cmp(m) < 0 {
+		k.add(instPath, kwPath+"/minimum", "number is below the minimum of %v", gabs.Wrap(obj["minimum"]).String())
+	}
+	if m, ok := toRat(obj["exclusiveMinimum"]); ok && inst.Cmp(m) <= 0 {
+		k.add(instPath, kwPath+"/exclusiveMinimum", "number must be greater than %v", gabs.Wrap(obj["exclusiveMinimum"]).String())
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// toRat converts a number of any representation into an exact rational with
+// gabs, such that numbers are compared with the same rules as gabs.Equal, which
+// is used for const and enum.
+func toRat(v interface{}) (*big.Rat, bool) {
+	r, err := gabs.Wrap(v).BigRat()
+	return r, err == nil
+}
+
+// schemaInt returns the value of a keyword that expects a non-negative
+// integer.
+func schemaInt(v interface{}) (int, bool) {
+	r, ok := toRat(v)
+	if !ok || !r.IsInt() || r.Sign() < 0 || !r.Num().IsInt64() {
+		return 0, false
+	}
+	return int(r.Num().Int64()), true
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	if _, ok := toRat(v); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func typeMatches(name string, v interface{}) bool {
+	if name == "integer" {
+		r, ok := toRat(v)
+		return ok && r.IsInt()
+	}
+	return typeName(v) == name
+}
+
+func equal(a, b interface{}) bool {
+	return gabs.Equal(gabs.Wrap(a), gabs.Wrap(b))
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,205 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+//------------------------------------------------------------------------------
+
+type shapeKind int
+
+const (
+	shapeKindNull shapeKind = 1 << iota
+	shapeKindBool
+	shapeKindInt
+	shapeKindFloat
+	shapeKindString
+	shapeKindObject
+	shapeKindArray
+	shapeKindUnknown
+)
+
+type stringFormat int
+
+// Formats of strings that are detected within samples, in order of priority.
+const (
+	stringFormatDateTime stringFormat = 1 << iota
+	stringFormatDate
+	stringFormatUUID
+	stringFormatEmail
+	stringFormatIPv4
+	stringFormatURI
+)
+
+var stringFormatNames = []struct {
+	format stringFormat
+	name   string
+}{
+	{stringFormatDateTime, "date-time"},
+	{stringFormatDate, "date"},
+	{stringFormatUUID, "uuid"},
+	{stringFormatEmail, "email"},
+	{stringFormatIPv4, "ipv4"},
+	{stringFormatURI, "uri"},
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// detectFormats returns the formats that a string satisfies.
+func detectFormats(s string) stringFormat {
+	var f stringFormat
+	if _, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		f |= stringFormatDateTime
+	}
+	if _, err := time.Parse("2006-01-02", s); err == nil {
+		f |= stringFormatDate
+	}
+	if uuidRegexp.MatchString(s) {
+		f |= stringFormatUUID
+	}
+	if addr, err := mail.ParseAddress(s); err == nil && addr.Address == s {
+		f |= stringFormatEmail
+	}
+	if ip := net.ParseIP(s); ip != nil && ip.To4() != nil {
+		f |= stringFormatIPv4
+	}
+	if u, err := url.Parse(s); err == nil && u.Scheme != "" && u.Host != "" {
+		f |= stringFormatURI
+	}
+	return f
+}
+
+// maxShapeValues is the number of distinct strings recorded for a shape.
+const maxShapeValues = 10
+
+// sampleShape is the merged shape of all sample values found at a position
+// within a set of samples.
+type sampleShape struct {
+	kinds shapeKind
+
+	// objects is the number of objects merged into the shape, and present is
+	// the number of objects of the parent shape that contained this field.
+	objects int
+	present int
+	fields  map[string]*sampleShape
+
+	elem *sampleShape
+
+	// strings is the number of strings merged into the shape, formats are the
+	// formats satisfied by all of them, and values counts the occurrences of
+	// each distinct string until there are more than maxShapeValues of them,
+	// at which point it is nil.
+	strings int
+	formats stringFormat
+	values  map[string]int
+
+	// min and max are the smallest and largest numbers merged into the shape
+	// in their original representation.
+	min, max       interface{}
+	minRat, maxRat *big.Rat
+}
+
+func (s *sampleShape) merge(v interface{}) {
+	switch t := v.(type) {
+	case nil:
+		s.kinds |= shapeKindNull
+	case bool:
+		s.kinds |= shapeKindBool
+	case string:
+		s.kinds |= shapeKindString
+		if s.strings == 0 {
+			s.formats = detectFormats(t)
+			s.values = map[string]int{}
+		} else if s.formats != 0 {
+			s.formats &= detectFormats(t)
+		}
+		s.strings++
+		if s.values != nil {
+			s.values[t]++
+			if len(s.values) > maxShapeValues {
+				s.values = nil
+			}
+		}
+	case map[string]interface{}:
+		s.kinds |= shapeKindObject
+		s.objects++
+		if s.fields == nil {
+			s.fields = map[string]*sampleShape{}
+		}
+		for k, fv := range t {
+			f, exists := s.fields[k]
+			if !exists {
+				f = &sampleShape{}
+				s.fields[k] = f
+			}
+			f.present++
+			f.merge(fv)
+		}
+	case []interface{}:
+		s.kinds |= shapeKindArray
+		if s.elem == nil {
+			s.elem = &sampleShape{}
+		}
+		for _, e := range t {
+			s.elem.merge(e)
+		}
+	default:
+		r, ok := toRat(v)
+		if !ok {
+			s.kinds |= shapeKindUnknown
+			return
+		}
+		if r.IsInt() && r.Num().IsInt64() {
+			s.kinds |= shapeKindInt
+		} else {
+			s.kinds |= shapeKindFloat
+		}
+		if s.minRat == nil || r.Cmp(s.minRat) < 0 {
+			s.min, s.minRat = v, r
+		}
+		if s.maxRat == nil || r.Cmp(s.maxRat) > 0 {
+			s.max, s.maxRat = v, r
+		}
+	}
+}
+
+// category returns the single kind that describes all non-null samples of the
+// shape, where integers and floats are both numbers. Returns zero if there
+// were no non-null samples and shapeKindUnknown if the samples are of
+// conflicting kinds.
+func (s *sampleShape) category() shapeKind {
+	switch k := s.kinds &^ shapeKindNull; k {
+	case 0, shapeKindBool, shapeKindInt, shapeKindFloat, shapeKindString, shapeKindObject, shapeKindArray:
+		return k
+	case shapeKindInt | shapeKindFloat:
+		return shapeKindFloat
+	}
+	return shapeKindUnknown
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,169 @@
+//go:build go1.21
+// +build go1.21
+
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"strconv"
+)
+
+//------------------------------------------------------------------------------
+
+// LogOpt is a functional option for the LogValuer method.
+type LogOpt func(o *logOpts)
+
+type logOpts struct {
+	maxDepth    int
+	maxArrayLen int
+	redactPaths [][]string
+}
+
+// LogOptMaxDepth limits the number of nested objects and arrays that are
+// rendered as groups, where deeper objects and arrays are replaced with the
+// placeholders "{...}" and "[...]". A depth of zero or less is unlimited.
+func LogOptMaxDepth(depth int) LogOpt {
+	return func(o *logOpts) {
+		o.maxDepth = depth
+	}
+}
+
+// LogOptMaxArrayLen limits the number of elements rendered for each array,
+// where the number of remaining elements is given by an attribute
+// "truncated". A length of zero or less is unlimited.
+func LogOptMaxArrayLen(length int) LogOpt {
+	return func(o *logOpts) {
+		o.maxArrayLen = length
+	}
+}
+
+// LogOptRedactPaths replaces the values at dot notation paths with the string
+// "[REDACTED]", a path segment '*' matches any object key or array index.
+// Paths are relative to the container being logged.
+func LogOptRedactPaths(paths ...string) LogOpt {
+	return func(o *logOpts) {
+		for _, p := range paths {
+			o.redactPaths = append(o.redactPaths, DotPathToSlice(p))
+		}
+	}
+}
+
+// LogValue implements slog.LogValuer, where objects are rendered as groups of
+// attributes sorted by key, arrays are rendered as groups keyed by index, and
+// empty objects and arrays are rendered as the strings "{}" and "[]" so that
+// they are not omitted by handlers.
+func (g *Container) LogValue() slog.Value {
+	return logValue(&logOpts{}, nil, g.Data(), 0)
+}
+
+// LogValuer returns a slog.LogValuer that renders the container following the
+// same rules as LogValue with options for limiting and redacting the output.
+func (g *Container) LogValuer(opts ...LogOpt) slog.LogValuer {
+	o := &logOpts{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return containerLogValuer{c: g, opts: o}
+}
+
+type containerLogValuer struct {
+	c    *Container
+	opts *logOpts
+}
+
+func (l containerLogValuer) LogValue() slog.Value {
+	return logValue(l.opts, nil, l.c.Data(), 0)
+}
+
+//------------------------------------------------------------------------------
+
+func (o *logOpts) childPath(path []string, seg string) []string {
+	if len(o.redactPaths) == 0 {
+		return nil
+	}
+	return append(path[:len(path):len(path)], seg)
+}
+
+func logValue(o *logOpts, path []string, v interface{}, depth int) slog.Value {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(t) == 0 {
+			return slog.StringValue("{}")
+		}
+		if o.maxDepth > 0 && depth >= o.maxDepth {
+			return slog.StringValue("{...}")
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		attrs := make([]slog.Attr, 0, len(keys))
+		for _, k := range keys {
+			attrs = append(attrs, logAttr(o, o.childPath(path, k), k, t[k], depth+1))
+		}
+		return slog.GroupValue(attrs...)
+	case []interface{}:
+		if len(t) == 0 {
+			return slog.StringValue("[]")
+		}
+		if o.maxDepth > 0 && depth >= o.maxDepth {
+			return slog.StringValue("[...]")
+		}
+		elements := t
+		if o.maxArrayLen > 0 && len(elements) > o.maxArrayLen {
+			elements = elements[:o.maxArrayLen]
+		}
+		attrs := make([]slog.Attr, 0, len(elements)+1)
+		for i, e := range elements {
+			k := strconv.Itoa(i)
+			attrs = append(attrs, logAttr(o, o.childPath(path, k), k, e, depth+1))
+		}
+		if remaining := len(t) - len(elements); remaining > 0 {
+			attrs = append(attrs, slog.Int("truncated", remaining))
+		}
+		return slog.GroupValue(attrs...)
+	case string:
+		return slog.StringValue(t)
+	case bool:
+		return slog.BoolValue(t)
+	case float64:
+		return slog.Float64Value(t)
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return slog.Int64Value(i)
+		}
+	}
+	return slog.AnyValue(v)
+}
+
+func logAttr(o *logOpts, path []string, key string, v interface{}, depth int) slog.Attr {
+	if len(path) > 0 && matchesAnyPath(o.redactPaths, path) {
+		return slog.String(key, "[REDACTED]")
+	}
+	return slog.Attr{Key: key, Value: logValue(o, path, v, depth)}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,96 @@
+//go:build go1.21
+// +build go1.21
+
+package gabs
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func logToString(t *testing.T, text bool, v interface{}) string {
+	t.Helper()
+	var buf bytes.Buffer
+	hOpts := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}
+	var h slog.Handler = slog.NewJSONHandler(&buf, hOpts)
+	if text {
+		h = slog.NewTextHandler(&buf, hOpts)
+	}
+	slog.New(h).Info("req", "body", v)
+	return buf.String()
+}
+
+func TestLogValue(t *testing.T) {
+	type testCase struct {
+		input  string
+		opts   []LogOpt
+		text   bool
+		output string
+	}
+	tests := []testCase{
+		{
+			input:  `{"b":{"c":[1,"two",true,null]},"a":"x"}`,
+			output: `{"msg":"req","body":{"a":"x","b":{"c":{"0":1,"1":"two","2":true,"3":null}}}}` + "\n",
+		},
+		{
+			input:  `{"b":{"c":[1,2]},"a":"x"}`,
+			text:   true,
+			output: `msg=req body.a=x body.b.c.0=1 body.b.c.1=2` + "\n",
+		},
+		{
+			input:  `{"empty":{},"arr":[]}`,
+			output: `{"msg":"req","body":{"arr":"[]","empty":"{}"}}` + "\n",
+		},
+		{
+			input:  `"scalar"`,
+			output: `{"msg":"req","body":"scalar"}` + "\n",
+		},
+		{
+			input:  `{"a":{"b":{"c":1}},"d":[[1]],"e":2}`,
+			opts:   []LogOpt{LogOptMaxDepth(2)},
+			output: `{"msg":"req","body":{"a":{"b":"{...}"},"d":{"0":"[...]"},"e":2}}` + "\n",
+		},
+		{
+			input:  `{"a":[1,2,3,4,5]}`,
+			opts:   []LogOpt{LogOptMaxArrayLen(2)},
+			output: `{"msg":"req","body":{"a":{"0":1,"1":2,"truncated":3}}}` + "\n",
+		},
+		{
+			input:  `{"user":{"name":"foo","password":"bar"},"tokens":[{"v":"a"},{"v":"b"}]}`,
+			opts:   []LogOpt{LogOptRedactPaths("user.password", "tokens.*.v")},
+			output: `{"msg":"req","body":{"tokens":{"0":{"v":"[REDACTED]"},"1":{"v":"[REDACTED]"}},"user":{"name":"foo","password":"[REDACTED]"}}}` + "\n",
+		},
+	}
+
+	for i, test := range tests {
+		c, err := ParseJSON([]byte(test.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v interface{} = c
+		if len(test.opts) > 0 {
+			v = c.LogValuer(test.opts...)
+		}
+		if exp, act := test.output, logToString(t, test.text, v); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestLogValueUseNumber(t *testing.T) {
+	c, err := ParseJSONWithOpts([]byte(`{"id":12345678901234567890,"n":10,"price":1.10}`), ParseOptUseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"msg":"req","body":{"id":12345678901234567890,"n":10,"price":1.10}}`+"\n", logToString(t, false, c); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+//------------------------------------------------------------------------------
+
+// Scan implements the sql.Scanner interface, allowing a container to be
+// scanned directly from a JSON column such as a PostgreSQL jsonb or MySQL JSON
+// column. The column value must be a JSON document as either []byte or a
+// string, and is parsed according to the same rules as UnmarshalJSON. A NULL
+// column results in a container of a nil value.
+func (g *Container) Scan(src interface{}) error {
+	switch t := src.(type) {
+	case nil:
+		g.object, g.lazy = nil, nil
+		return nil
+	case []byte:
+		return g.UnmarshalJSON(t)
+	case string:
+		return g.UnmarshalJSON([]byte(t))
+	}
+	return fmt.Errorf("cannot scan value of type %T into a container", src)
+}
+
+// Value implements the driver.Valuer interface, allowing a container to be
+// written to a JSON column. The JSON document is returned as a string, which
+// drivers accept for JSON columns, rather than []byte, which some drivers
+// treat as binary data. A nil container or a container of a nil value results
+// in NULL.
+func (g *Container) Value() (driver.Value, error) {
+	if g.encodable() == nil {
+		return nil, nil
+	}
+	b, err := g.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+//------------------------------------------------------------------------------
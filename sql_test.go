@@ -0,0 +1,192 @@
+package gabs
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeJSONDriver is a database/sql driver that supports two statements, one
+// that inserts a document by key and one that selects a document by key, where
+// documents are stored exactly as they were received and returned as []byte.
+type fakeJSONDriver struct {
+	mut  sync.Mutex
+	docs map[string]driver.Value
+}
+
+func (d *fakeJSONDriver) Open(name string) (driver.Conn, error) {
+	return &fakeJSONConn{d: d}, nil
+}
+
+type fakeJSONConn struct {
+	d *fakeJSONDriver
+}
+
+func (c *fakeJSONConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeJSONStmt{d: c.d, insert: strings.HasPrefix(query, "INSERT")}, nil
+}
+
+func (c *fakeJSONConn) Close() error {
+	return nil
+}
+
+func (c *fakeJSONConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions not supported")
+}
+
+type fakeJSONStmt struct {
+	d      *fakeJSONDriver
+	insert bool
+}
+
+func (s *fakeJSONStmt) Close() error {
+	return nil
+}
+
+func (s *fakeJSONStmt) NumInput() int {
+	if s.insert {
+		return 2
+	}
+	return 1
+}
+
+func (s *fakeJSONStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mut.Lock()
+	s.d.docs[args[0].(string)] = args[1]
+	s.d.mut.Unlock()
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeJSONStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mut.Lock()
+	v, exists := s.d.docs[args[0].(string)]
+	s.d.mut.Unlock()
+	if str, ok := v.(string); ok {
+		v = []byte(str)
+	}
+	return &fakeJSONRows{value: v, done: !exists}, nil
+}
+
+type fakeJSONRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *fakeJSONRows) Columns() []string {
+	return []string{"doc"}
+}
+
+func (r *fakeJSONRows) Close() error {
+	return nil
+}
+
+func (r *fakeJSONRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	dest[0], r.done = r.value, true
+	return nil
+}
+
+var fakeJSONDriverInstance = &fakeJSONDriver{docs: map[string]driver.Value{}}
+
+func init() {
+	sql.Register("gabs_fake_json", fakeJSONDriverInstance)
+}
+
+func TestSQLRoundTrip(t *testing.T) {
+	db, err := sql.Open("gabs_fake_json", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	doc, err := ParseJSON([]byte(`{"name":"foo","tags":["a","b"],"count":3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.Exec("INSERT INTO docs (id, doc) VALUES (?, ?)", "first", doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.Exec("INSERT INTO docs (id, doc) VALUES (?, ?)", "second", (*Container)(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	fakeJSONDriverInstance.mut.Lock()
+	stored := fakeJSONDriverInstance.docs["first"]
+	nullStored := fakeJSONDriverInstance.docs["second"]
+	fakeJSONDriverInstance.mut.Unlock()
+	if exp, act := `{"count":3,"name":"foo","tags":["a","b"]}`, stored; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if nullStored != nil {
+		t.Errorf("Expected NULL, received: %v", nullStored)
+	}
+
+	var res Container
+	if err = db.QueryRow("SELECT doc FROM docs WHERE id = ?", "first").Scan(&res); err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(doc, &res) {
+		t.Errorf("Wrong result: %v != %v", res.String(), doc.String())
+	}
+	if exp, act := "b", res.Path("tags.1").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	nullRes := New()
+	if err = db.QueryRow("SELECT doc FROM docs WHERE id = ?", "second").Scan(nullRes); err != nil {
+		t.Fatal(err)
+	}
+	if nullRes.Data() != nil {
+		t.Errorf("Expected nil, received: %v", nullRes.Data())
+	}
+}
+
+func TestSQLScan(t *testing.T) {
+	type testCase struct {
+		input  interface{}
+		output string
+		err    bool
+	}
+	tests := []testCase{
+		{input: []byte(`{"a":1}`), output: `{"a":1}`},
+		{input: `[true]`, output: `[true]`},
+		{input: nil, output: `null`},
+		{input: []byte(`{"a":`), err: true},
+		{input: int64(10), err: true},
+	}
+
+	for i, test := range tests {
+		c := New()
+		err := c.Scan(test.input)
+		if test.err {
+			if err == nil {
+				t.Errorf("[%d] Expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("[%d] Unexpected error: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, c.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+
+	c, err := ParseJSONWithOpts([]byte(`{"price":1.10}`), ParseOptUseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Scan([]byte(`{"price":2.50}`)); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"price":2.50}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+//------------------------------------------------------------------------------
+
+// ArrayStream iterates the elements of an array within a JSON document read
+// from an io.Reader, where only a single element is held in memory at a time.
+// Values preceding the array within the document are skipped without being
+// decoded.
+type ArrayStream struct {
+	dec     *json.Decoder
+	path    []string
+	started bool
+	index   int
+	err     error
+}
+
+// NewArrayStream creates a stream of the elements of an array found at a dot
+// notation path within a JSON document, where the path may optionally end
+// with the segment '*'. Path segments that encounter an array are treated as
+// indexes. An empty path or "*" selects a top level array.
+func NewArrayStream(r io.Reader, path string) *ArrayStream {
+	var hierarchy []string
+	if path != "" {
+		hierarchy = DotPathToSlice(path)
+		if hierarchy[len(hierarchy)-1] == "*" {
+			hierarchy = hierarchy[:len(hierarchy)-1]
+		}
+	}
+	return &ArrayStream{
+		dec:   json.NewDecoder(r),
+		path:  hierarchy,
+		index: -1,
+	}
+}
+
+// Next returns the next element of the array. Returns io.EOF after the last
+// element, ErrNotFound if the path does not exist within the document and
+// ErrNotArray if the value at the path is not an array. Once an error has
+// been returned all subsequent calls return the same error.
+func (s *ArrayStream) Next() (*Container, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if !s.started {
+		s.started = true
+		if s.err = s.seek(); s.err != nil {
+			return nil, s.err
+		}
+	}
+	if !s.dec.More() {
+		if _, err := s.token(); err != nil {
+			s.err = err
+		} else {
+			s.err = io.EOF
+		}
+		return nil, s.err
+	}
+
+	var gabs Container
+	if err := s.dec.Decode(&gabs.object); err != nil {
+		s.err = err
+		return nil, err
+	}
+	s.index++
+	return &gabs, nil
+}
+
+// Index returns the index of the most recently returned element within the
+// array, or -1 if no elements have been returned.
+func (s *ArrayStream) Index() int {
+	return s.index
+}
+
+// seek consumes the document up to and including the opening delimiter of the
+// target array.
+func (s *ArrayStream) seek() error {
+	for i := 0; i <= len(s.path); i++ {
+		tok, err := s.token()
+		if err != nil {
+			return err
+		}
+		delim, isDelim := tok.(json.Delim)
+		if i == len(s.path) {
+			if !isDelim || delim != '[' {
+				return ErrNotArray
+			}
+			return nil
+		}
+		if !isDelim {
+			return ErrNotFound
+		}
+
+		seg := s.path[i]
+		switch delim {
+		case '{':
+			if err = s.seekKey(seg); err != nil {
+				return err
+			}
+		case '[':
+			index, err := strconv.Atoi(seg)
+			if err != nil || index < 0 {
+				return ErrNotFound
+			}
+			for ; index > 0; index-- {
+				if !s.dec.More() {
+					return ErrNotFound
+				}
+				if err = s.skipValue(); err != nil {
+					return err
+				}
+			}
+			if !s.dec.More() {
+				return ErrNotFound
+			}
+		}
+	}
+	return nil
+}
+
+// seekKey consumes the members of an object until the value of key is next.
+func (s *ArrayStream) seekKey(key string) error {
+	for s.dec.More() {
+		tok, err := s.token()
+		if err != nil {
+			return err
+		}
+		if tok == key {
+			return nil
+		}
+		if err = s.skipValue(); err != nil {
+			return err
+		}
+	}
+	return ErrNotFound
+}
+
+// skipValue consumes the next value, tokenizing nested values rather than
+// decoding them.
+func (s *ArrayStream) skipValue() error {
+	depth := 0
+	for {
+		tok, err := s.token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			default:
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func (s *ArrayStream) token() (json.Token, error) {
+	tok, err := s.dec.Token()
+	if err == io.EOF {
+		return nil, fmt.Errorf("unexpected end of JSON input: %w", io.ErrUnexpectedEOF)
+	}
+	return tok, err
+}
+
+//------------------------------------------------------------------------------
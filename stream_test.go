@@ -0,0 +1,161 @@
+package gabs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func readArrayStream(s *ArrayStream) ([]string, error) {
+	var results []string
+	for {
+		c, err := s.Next()
+		if err == io.EOF {
+			return results, nil
+		}
+		if err != nil {
+			return results, err
+		}
+		results = append(results, c.String())
+	}
+}
+
+func TestArrayStream(t *testing.T) {
+	type testCase struct {
+		input  string
+		path   string
+		output string
+	}
+	tests := []testCase{
+		{
+			input:  `[1, "two", {"three": 3}, [4]]`,
+			path:   "",
+			output: `1|"two"|{"three":3}|[4]`,
+		},
+		{
+			input:  `[]`,
+			path:   "*",
+			output: ``,
+		},
+		{
+			input:  `{"meta": {"skip": [1, {"a": [2]}]}, "data": {"count": 2, "items": [{"id": 1}, {"id": 2}]}, "after": true}`,
+			path:   "data.items.*",
+			output: `{"id":1}|{"id":2}`,
+		},
+		{
+			input:  `{"data": {"items": [{"id": 1}]}}`,
+			path:   "data.items",
+			output: `{"id":1}`,
+		},
+		{
+			input:  `{"pages": [{"items": [1]}, {"items": [2, 3]}]}`,
+			path:   "pages.1.items.*",
+			output: `2|3`,
+		},
+		{
+			input:  `{"a.b": {"c": [true]}}`,
+			path:   "a~1b.c",
+			output: `true`,
+		},
+	}
+
+	for i, test := range tests {
+		results, err := readArrayStream(NewArrayStream(strings.NewReader(test.input), test.path))
+		if err != nil {
+			t.Errorf("[%d] Failed to stream: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, strings.Join(results, "|"); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestArrayStreamErrors(t *testing.T) {
+	type testCase struct {
+		input string
+		path  string
+		err   error
+	}
+	tests := []testCase{
+		{input: `{"a": [1]}`, path: "b", err: ErrNotFound},
+		{input: `{"a": {"b": 1}}`, path: "a.c.*", err: ErrNotFound},
+		{input: `{"a": [1]}`, path: "a.1", err: ErrNotFound},
+		{input: `{"a": [1]}`, path: "a.x", err: ErrNotFound},
+		{input: `{"a": "str"}`, path: "a.b", err: ErrNotFound},
+		{input: `{"a": {"b": 1}}`, path: "a", err: ErrNotArray},
+		{input: `{"a": 1}`, path: "a", err: ErrNotArray},
+		{input: `[1, 2`, path: ""},
+		{input: `{"a": `, path: "a", err: io.ErrUnexpectedEOF},
+	}
+
+	for i, test := range tests {
+		s := NewArrayStream(strings.NewReader(test.input), test.path)
+		_, err := readArrayStream(s)
+		if test.err == nil {
+			if err == nil {
+				t.Errorf("[%d] Expected error", i)
+			}
+		} else if !errors.Is(err, test.err) {
+			t.Errorf("[%d] Wrong error: %v != %v", i, err, test.err)
+		}
+		if _, again := s.Next(); again != err {
+			t.Errorf("[%d] Expected repeated error: %v != %v", i, again, err)
+		}
+	}
+}
+
+// generatedArray produces a large JSON document without holding it in memory.
+type generatedArray struct {
+	n, i    int
+	pending []byte
+}
+
+func (g *generatedArray) Read(p []byte) (int, error) {
+	for len(g.pending) == 0 {
+		switch {
+		case g.i == 0:
+			g.pending = []byte(`{"items":[`)
+		case g.i <= g.n:
+			sep := ","
+			if g.i == 1 {
+				sep = ""
+			}
+			g.pending = []byte(fmt.Sprintf(`%v{"id":%v,"padding":"%v"}`, sep, g.i-1, strings.Repeat("x", 64)))
+		case g.i == g.n+1:
+			g.pending = []byte(`]}`)
+		default:
+			return 0, io.EOF
+		}
+		g.i++
+	}
+	n := copy(p, g.pending)
+	g.pending = g.pending[n:]
+	return n, nil
+}
+
+func TestArrayStreamLarge(t *testing.T) {
+	s := NewArrayStream(&generatedArray{n: 100000}, "items")
+	count := 0
+	for {
+		c, err := s.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp, act := float64(count), c.Path("id").Data(); exp != act {
+			t.Fatalf("Wrong id: %v != %v", act, exp)
+		}
+		if exp, act := count, s.Index(); exp != act {
+			t.Fatalf("Wrong index: %v != %v", act, exp)
+		}
+		count++
+	}
+	if exp, act := 100000, count; exp != act {
+		t.Errorf("Wrong count: %v != %v", act, exp)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+//------------------------------------------------------------------------------
+
+// DecodeOpt is a functional option for the Decode method.
+type DecodeOpt func(o *decodeOpts)
+
+type decodeOpts struct {
+	disallowUnknownFields bool
+}
+
+// DecodeOptDisallowUnknownFields causes Decode to return an error when an
+// object contains a key that does not match any exported field of the target
+// struct.
+func DecodeOptDisallowUnknownFields() DecodeOpt {
+	return func(o *decodeOpts) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// Decode binds the value of this container into target, which must be a
+// pointer, following the same rules as json.Unmarshal. Struct fields are
+// therefore matched by their json tags, and types implementing
+// json.Unmarshaler are respected. Returns ErrNotFound if the container is nil.
+func (g *Container) Decode(target interface{}, opts ...DecodeOpt) error {
+	if g == nil {
+		return ErrNotFound
+	}
+	var o decodeOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	data, err := json.Marshal(g.encodable())
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if o.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	return dec.Decode(target)
+}
+
+//------------------------------------------------------------------------------
+
+// structToGeneric converts a Go value into its generic JSON form of maps,
+// slices and primitives by encoding it, so that json tags, omitempty and
+// json.Marshaler implementations are all respected.
+func structToGeneric(v interface{}, opts []ParseOpt) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err = newParseOpts(opts).unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// WrapStruct converts a Go value such as a struct into its generic JSON form
+// and wraps it in a *Container, where unlike Wrap the result can be navigated
+// by its json field names. The conversion follows the same rules as
+// json.Marshal. By default numbers are converted into float64 values, which
+// can be changed with ParseOptUseNumber.
+func WrapStruct(v interface{}, opts ...ParseOpt) (*Container, error) {
+	generic, err := structToGeneric(v, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Container{object: generic}, nil
+}
+
+// SetStruct converts a Go value such as a struct into its generic JSON form,
+// following the same rules as WrapStruct, and sets it at a path within the
+// container.
+func (g *Container) SetStruct(value interface{}, hierarchy ...string) (*Container, error) {
+	generic, err := structToGeneric(value, nil)
+	if err != nil {
+		return nil, err
+	}
+	return g.Set(generic, hierarchy...)
+}
+
+// SetStructP does the same as SetStruct but using a dot notation JSON path.
+func (g *Container) SetStructP(value interface{}, path string) (*Container, error) {
+	return g.SetStruct(value, DotPathToSlice(path)...)
+}
+
+//------------------------------------------------------------------------------
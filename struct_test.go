@@ -0,0 +1,126 @@
+package gabs
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type testAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city,omitempty"`
+}
+
+type testPerson struct {
+	Name    string       `json:"name"`
+	Age     int          `json:"age"`
+	ID      int64        `json:"id"`
+	Tags    []string     `json:"tags"`
+	Address *testAddress `json:"address,omitempty"`
+	Secret  string       `json:"-"`
+}
+
+func TestDecode(t *testing.T) {
+	c, err := ParseJSON([]byte(`{"people":[{"name":"Ash","age":30,"id":1,"tags":["a","b"],"address":{"street":"Main"}},{"name":"Bo","age":7,"unknown":true}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p testPerson
+	if err = c.Path("people.0").Decode(&p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Ash" || p.Age != 30 || len(p.Tags) != 2 || p.Address == nil || p.Address.Street != "Main" {
+		t.Errorf("Wrong result: %+v", p)
+	}
+
+	var people []testPerson
+	if err = c.S("people").Decode(&people); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := 2, len(people); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	if err = c.Path("people.1").Decode(&p, DecodeOptDisallowUnknownFields()); err == nil {
+		t.Error("Expected error from unknown field")
+	}
+	if err = c.Path("people.0").Decode(&p, DecodeOptDisallowUnknownFields()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err = c.Path("does.not.exist").Decode(&p); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, received: %v", err)
+	}
+	if err = c.Path("people.0.name").Decode(&p); err == nil {
+		t.Error("Expected error from type mismatch")
+	}
+}
+
+func TestDecodeUseNumber(t *testing.T) {
+	c, err := ParseJSONWithOpts([]byte(`{"id":9007199254740993}`), ParseOptUseNumber())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p testPerson
+	if err = c.Decode(&p); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := int64(9007199254740993), p.ID; exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func TestWrapStruct(t *testing.T) {
+	p := testPerson{
+		Name:    "Ash",
+		Age:     30,
+		ID:      9007199254740993,
+		Tags:    []string{"a"},
+		Address: &testAddress{Street: "Main"},
+		Secret:  "hidden",
+	}
+
+	c, err := WrapStruct(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := "Main", c.Path("address.street").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := 30.0, c.Path("age").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if c.Exists("Secret") || c.Exists("address", "city") {
+		t.Errorf("Unexpected fields: %v", c.String())
+	}
+
+	if c, err = WrapStruct(&p, ParseOptUseNumber()); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := json.Number("9007199254740993"), c.Path("id").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	if _, err = WrapStruct(func() {}); err == nil {
+		t.Error("Expected error from unsupported type")
+	}
+}
+
+func TestSetStruct(t *testing.T) {
+	c := New()
+	if _, err := c.SetStructP(testAddress{Street: "Main", City: "Town"}, "people.ash.address"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.SetStruct([]testAddress{{Street: "Side"}}, "people", "bo", "addresses"); err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := `{"people":{"ash":{"address":{"city":"Town","street":"Main"}},"bo":{"addresses":[{"street":"Side"}]}}}`, c.String(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if exp, act := "Side", c.Path("people.bo.addresses.0.street").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+	if _, err := c.SetStruct(make(chan int), "bad"); err == nil {
+		t.Error("Expected error from unsupported type")
+	}
+}
@@ -0,0 +1,418 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//------------------------------------------------------------------------------
+
+// XMLOpt is a functional option for the ParseXML function.
+type XMLOpt func(o *xmlOpts)
+
+type xmlOpts struct {
+	forceArrays  [][]string
+	keepPrefixes bool
+}
+
+// XMLOptForceArray causes elements found at dot notation paths to always be
+// parsed into arrays, even when they only occur once. Paths begin with the
+// name of the root element, and a path segment '*' matches any element name.
+func XMLOptForceArray(paths ...string) XMLOpt {
+	return func(o *xmlOpts) {
+		for _, p := range paths {
+			o.forceArrays = append(o.forceArrays, DotPathToSlice(p))
+		}
+	}
+}
+
+// XMLOptKeepNamespacePrefixes retains namespace prefixes in element and
+// attribute names (e.g. "soap:Envelope") and keeps namespace declarations as
+// "@xmlns" attributes, allowing namespaced documents to be round tripped. By
+// default prefixes and namespace declarations are discarded.
+func XMLOptKeepNamespacePrefixes() XMLOpt {
+	return func(o *xmlOpts) {
+		o.keepPrefixes = true
+	}
+}
+
+// ParseXML parses an XML document into a *Container. The result is an object
+// with a single key, the name of the root element, and elements are converted
+// using the following convention:
+//
+// - An element containing only text becomes a string, and an empty element
+// becomes null.
+//
+// - An element with attributes or child elements becomes an object, where
+// attributes are stored under their name prefixed with '@', child elements
+// under their name, and any text under the key "#text".
+//
+// - Child elements sharing a name are collected into an array in document
+// order.
+//
+// Text is trimmed of surrounding whitespace and is never converted into other
+// types. Comments, processing instructions and the relative order of
+// differently named elements are not preserved.
+func ParseXML(r io.Reader, opts ...XMLOpt) (*Container, error) {
+	p := xmlParser{dec: xml.NewDecoder(r)}
+	for _, opt := range opts {
+		opt(&p.opts)
+	}
+
+	for {
+		tok, err := p.dec.RawToken()
+		if err != nil {
+			if err == io.EOF {
+				err = errors.New("xml: no root element found")
+			}
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := p.name(t.Name)
+			p.path = append(p.path[:0], name)
+			v, err := p.parseElement(t)
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expectEnd(); err != nil {
+				return nil, err
+			}
+			return &Container{object: map[string]interface{}{name: v}}, nil
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				return nil, errors.New("xml: text found outside of root element")
+			}
+		}
+	}
+}
+
+// XML encodes an element as an XML document following the same convention as
+// ParseXML. The element must be an object with a single key, which becomes the
+// root element. Object keys are sorted in order for the output to be
+// deterministic, with attributes written first, followed by text and then
+// child elements. An error is returned if an element or attribute name is not a
+// valid XML name.
+func (g *Container) XML() ([]byte, error) {
+	m, ok := g.Data().(map[string]interface{})
+	if !ok {
+		return nil, ErrNotObj
+	}
+	if len(m) != 1 {
+		return nil, fmt.Errorf("xml: expected a single root element, found %v", len(m))
+	}
+	var buf bytes.Buffer
+	for k, v := range m {
+		if err := writeXMLElement(&buf, k, v); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+//------------------------------------------------------------------------------
+
+var errXMLMaxDepth = errors.New("xml: exceeded maximum nesting depth")
+
+const xmlMaxDepth = 10000
+
+type xmlParser struct {
+	dec  *xml.Decoder
+	opts xmlOpts
+
+	// path is the stack of element names leading to the element being parsed.
+	path []string
+}
+
+func (p *xmlParser) name(n xml.Name) string {
+	if p.opts.keepPrefixes && n.Space != "" {
+		return n.Space + ":" + n.Local
+	}
+	return n.Local
+}
+
+// expectEnd checks that nothing but whitespace, comments and processing
+// instructions follow the root element.
+func (p *xmlParser) expectEnd() error {
+	for {
+		tok, err := p.dec.RawToken()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			return errors.New("xml: multiple root elements found")
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				return errors.New("xml: text found outside of root element")
+			}
+		}
+	}
+}
+
+func (p *xmlParser) parseElement(start xml.StartElement) (interface{}, error) {
+	if len(p.path) > xmlMaxDepth {
+		return nil, errXMLMaxDepth
+	}
+
+	obj := map[string]interface{}{}
+	for _, a := range start.Attr {
+		isNamespaceDecl := a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns")
+		if isNamespaceDecl && !p.opts.keepPrefixes {
+			continue
+		}
+		key := "@" + p.name(a.Name)
+		if _, exists := obj[key]; exists {
+			return nil, fmt.Errorf("xml: duplicate attribute '%v' in element <%v>", key[1:], p.name(start.Name))
+		}
+		obj[key] = a.Value
+	}
+
+	var text []byte
+	for {
+		tok, err := p.dec.RawToken()
+		if err != nil {
+			if err == io.EOF {
+				err = fmt.Errorf("xml: unexpected EOF, element <%v> is not closed", p.name(start.Name))
+			}
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := p.name(t.Name)
+			p.path = append(p.path, name)
+			v, err := p.parseElement(t)
+			if err != nil {
+				return nil, err
+			}
+			forceArray := matchesAnyPath(p.opts.forceArrays, p.path)
+			p.path = p.path[:len(p.path)-1]
+			switch existing := obj[name].(type) {
+			case nil:
+				if _, exists := obj[name]; exists {
+					obj[name] = []interface{}{nil, v}
+				} else if forceArray {
+					obj[name] = []interface{}{v}
+				} else {
+					obj[name] = v
+				}
+			case []interface{}:
+				obj[name] = append(existing, v)
+			default:
+				obj[name] = []interface{}{existing, v}
+			}
+		case xml.EndElement:
+			if t.Name != start.Name {
+				return nil, fmt.Errorf(
+					"xml: element <%v> closed by </%v>", p.name(start.Name), p.name(t.Name),
+				)
+			}
+			textStr := string(bytes.TrimSpace(text))
+			if len(obj) == 0 {
+				if textStr == "" {
+					return nil, nil
+				}
+				return textStr, nil
+			}
+			if textStr != "" {
+				obj["#text"] = textStr
+			}
+			return obj, nil
+		case xml.CharData:
+			text = append(text, t...)
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func xmlScalarString(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case json.Number:
+		return t.String(), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	if len(b) > 0 && (b[0] == '{' || b[0] == '[') {
+		return "", fmt.Errorf("xml: value of type %T cannot be written as text", v)
+	}
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		return s, nil
+	}
+	return string(b), nil
+}
+
+// isXMLName returns whether a string matches the Name production of the XML
+// specification.
+func isXMLName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r == utf8.RuneError {
+			return false
+		}
+		if isXMLNameStartChar(r) {
+			continue
+		}
+		if i == 0 {
+			return false
+		}
+		switch {
+		case r == '-', r == '.', r >= '0' && r <= '9', r == 0xB7,
+			r >= 0x300 && r <= 0x36F, r >= 0x203F && r <= 0x2040:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isXMLNameStartChar(r rune) bool {
+	switch {
+	case r == ':', r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z',
+		r >= 0xC0 && r <= 0xD6, r >= 0xD8 && r <= 0xF6, r >= 0xF8 && r <= 0x2FF,
+		r >= 0x370 && r <= 0x37D, r >= 0x37F && r <= 0x1FFF, r >= 0x200C && r <= 0x200D,
+		r >= 0x2070 && r <= 0x218F, r >= 0x2C00 && r <= 0x2FEF, r >= 0x3001 && r <= 0xD7FF,
+		r >= 0xF900 && r <= 0xFDCF, r >= 0xFDF0 && r <= 0xFFFD, r >= 0x10000 && r <= 0xEFFFF:
+		return true
+	}
+	return false
+}
+
+func writeXMLElement(buf *bytes.Buffer, name string, v interface{}) error {
+	if !isXMLName(name) {
+		return fmt.Errorf("xml: invalid element name '%v'", name)
+	}
+	switch t := v.(type) {
+	case []interface{}:
+		for _, e := range t {
+			if _, isArray := e.([]interface{}); isArray {
+				return fmt.Errorf("xml: element '%v' contains a nested array", name)
+			}
+			if err := writeXMLElement(buf, name, e); err != nil {
+				return err
+			}
+		}
+		return nil
+	case nil:
+		buf.WriteString("<" + name + "/>")
+		return nil
+	case map[string]interface{}:
+		return writeXMLObject(buf, name, t)
+	}
+	if _, isScalar := v.(string); !isScalar {
+		if generic, err := toGeneric(v); err == nil {
+			switch generic.(type) {
+			case map[string]interface{}, []interface{}:
+				return writeXMLElement(buf, name, generic)
+			}
+		}
+	}
+	text, err := xmlScalarString(v)
+	if err != nil {
+		return err
+	}
+	buf.WriteString("<" + name + ">")
+	if err = xml.EscapeText(buf, []byte(text)); err != nil {
+		return err
+	}
+	buf.WriteString("</" + name + ">")
+	return nil
+}
+
+func writeXMLObject(buf *bytes.Buffer, name string, obj map[string]interface{}) error {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf.WriteString("<" + name)
+	var children []string
+	for _, k := range keys {
+		if !strings.HasPrefix(k, "@") {
+			if k != "#text" {
+				children = append(children, k)
+			}
+			continue
+		}
+		if !isXMLName(k[1:]) {
+			return fmt.Errorf("xml: invalid attribute name '%v'", k[1:])
+		}
+		value, err := xmlScalarString(obj[k])
+		if err != nil {
+			return err
+		}
+		buf.WriteString(" " + k[1:] + `="`)
+		if err = xml.EscapeText(buf, []byte(value)); err != nil {
+			return err
+		}
+		buf.WriteString(`"`)
+	}
+
+	text, hasText := obj["#text"]
+	if !hasText && len(children) == 0 {
+		buf.WriteString("/>")
+		return nil
+	}
+	buf.WriteString(">")
+	if hasText && text != nil {
+		textStr, err := xmlScalarString(text)
+		if err != nil {
+			return err
+		}
+		if err = xml.EscapeText(buf, []byte(textStr)); err != nil {
+			return err
+		}
+	}
+	for _, k := range children {
+		if err := writeXMLElement(buf, k, obj[k]); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("</" + name + ">")
+	return nil
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,187 @@
+package gabs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseXML(t *testing.T) {
+	type testCase struct {
+		input  string
+		opts   []XMLOpt
+		output string
+	}
+	tests := []testCase{
+		{
+			input:  `<a>hello</a>`,
+			output: `{"a":"hello"}`,
+		},
+		{
+			input:  `<a/>`,
+			output: `{"a":null}`,
+		},
+		{
+			input:  `<?xml version="1.0"?><!-- comment --><a id="1">text</a>`,
+			output: `{"a":{"#text":"text","@id":"1"}}`,
+		},
+		{
+			input: `<a>
+	<b>1</b>
+	<b>2</b>
+	<c><d>x</d></c>
+</a>`,
+			output: `{"a":{"b":["1","2"],"c":{"d":"x"}}}`,
+		},
+		{
+			input:  `<a><b>1</b><c/><b/></a>`,
+			output: `{"a":{"b":["1",null],"c":null}}`,
+		},
+		{
+			input:  `<a><b>1</b><c><b>2</b></c></a>`,
+			opts:   []XMLOpt{XMLOptForceArray("a.b")},
+			output: `{"a":{"b":["1"],"c":{"b":"2"}}}`,
+		},
+		{
+			input:  `<a><b>1</b><c><b>2</b></c></a>`,
+			opts:   []XMLOpt{XMLOptForceArray("a.*.b")},
+			output: `{"a":{"b":"1","c":{"b":["2"]}}}`,
+		},
+		{
+			input:  `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><m:Price xmlns:m="urn:x" m:currency="EUR">5</m:Price></soap:Body></soap:Envelope>`,
+			output: `{"Envelope":{"Body":{"Price":{"#text":"5","@currency":"EUR"}}}}`,
+		},
+		{
+			input:  `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body/></soap:Envelope>`,
+			opts:   []XMLOpt{XMLOptKeepNamespacePrefixes()},
+			output: `{"soap:Envelope":{"@xmlns:soap":"http://schemas.xmlsoap.org/soap/envelope/","soap:Body":null}}`,
+		},
+	}
+
+	for i, test := range tests {
+		c, err := ParseXML(strings.NewReader(test.input), test.opts...)
+		if err != nil {
+			t.Errorf("[%d] Failed to parse: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, c.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestParseXMLText(t *testing.T) {
+	c, err := ParseXML(strings.NewReader(`<a><![CDATA[<not a tag>]]> &amp; more</a>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := "<not a tag> & more", c.Path("a").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
+
+func TestParseXMLErrors(t *testing.T) {
+	tests := []string{
+		``,
+		`   `,
+		`<a>`,
+		`<a></b>`,
+		`<a/><b/>`,
+		`<a/>text`,
+		`text<a/>`,
+		`<a x="1" x="2"/>`,
+	}
+
+	for i, test := range tests {
+		if _, err := ParseXML(strings.NewReader(test)); err == nil {
+			t.Errorf("[%d] Expected error from '%v'", i, test)
+		}
+	}
+
+	deep := strings.Repeat("<a>", xmlMaxDepth+1) + strings.Repeat("</a>", xmlMaxDepth+1)
+	if _, err := ParseXML(strings.NewReader(deep)); err != errXMLMaxDepth {
+		t.Errorf("Expected depth error, received: %v", err)
+	}
+	deep = strings.Repeat("<a>", xmlMaxDepth) + strings.Repeat("</a>", xmlMaxDepth)
+	if _, err := ParseXML(strings.NewReader(deep)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestXML(t *testing.T) {
+	type testCase struct {
+		input  string
+		output string
+	}
+	tests := []testCase{
+		{
+			input:  `{"a":"hello"}`,
+			output: `<a>hello</a>`,
+		},
+		{
+			input:  `{"a":null}`,
+			output: `<a/>`,
+		},
+		{
+			input:  `{"a":{"c":[1,true,null],"b":"x < y","@id":"a\"b","#text":"t"}}`,
+			output: `<a id="a&#34;b">t<b>x &lt; y</b><c>1</c><c>true</c><c/></a>`,
+		},
+		{
+			input:  `{"a":{"@id":1}}`,
+			output: `<a id="1"/>`,
+		},
+		{
+			input:  `{"ns:a":{"@x-y.z":1,"_b":"c","\u00e9t\u00e91":null}}`,
+			output: "<ns:a x-y.z=\"1\"><_b>c</_b><\u00e9t\u00e91/></ns:a>",
+		},
+	}
+
+	for i, test := range tests {
+		c, err := ParseJSON([]byte(test.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := c.XML()
+		if err != nil {
+			t.Errorf("[%d] Failed to encode: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, string(b); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+
+	for i, test := range []string{
+		`[]`, `{}`, `{"a":1,"b":2}`, `{"a":[[1]]}`, `{"a":{"@b":{"c":1}}}`,
+		`{"a><evil x=\"1\"":1}`, `{"a":{"@b c":1}}`, `{"1bad":1}`, `{"a":{"b\u0000":1}}`, `{"a":{"@":1}}`,
+	} {
+		c, err := ParseJSON([]byte(test))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = c.XML(); err == nil {
+			t.Errorf("[%d] Expected error from '%v'", i, test)
+		}
+	}
+}
+
+func TestXMLRoundTrip(t *testing.T) {
+	input := `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">` +
+		`<soap:Body><order id="7"><item sku="a">first</item><item sku="b">second</item><note/></order></soap:Body>` +
+		`</soap:Envelope>`
+
+	c, err := ParseXML(strings.NewReader(input), XMLOptKeepNamespacePrefixes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := "second", c.Path("soap:Envelope.soap:Body.order.item.1.#text").Data(); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	b, err := c.XML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := input, string(b); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+}
@@ -0,0 +1,1303 @@
+// Copyright (c) 2019 Ashley Jeffs
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package gabs
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//------------------------------------------------------------------------------
+
+// ErrMultipleDocuments is returned when parsing a single document from input
+// that contains more than one.
+var ErrMultipleDocuments = errors.New("input contained multiple documents")
+
+// ParseYAML parses a YAML document into a *Container. Mappings are converted
+// into map[string]interface{} and sequences into []interface{}, with scalars
+// resolved according to the YAML 1.2 core schema. Numbers are parsed as
+// float64 in order to match the types produced by ParseJSON, and scalars tagged
+// !!binary are decoded into []byte.
+//
+// Mapping keys that are not strings are converted into their JSON
+// representation, e.g. the key `1.0` becomes "1", `true` becomes "true" and a
+// null key becomes "null". Anchors and aliases are resolved during parsing,
+// where each alias receives its own copy of the anchored value, and merge keys
+// (`<<`) are applied. An error is returned if the aliases of a document expand
+// into more than a million nodes.
+//
+// Returns ErrMultipleDocuments if the input contains more than one document,
+// in which case ParseYAMLStream should be used instead.
+func ParseYAML(sample []byte) (*Container, error) {
+	docs, err := ParseYAMLStream(sample)
+	if err != nil {
+		return nil, err
+	}
+	switch len(docs) {
+	case 0:
+		return &Container{object: nil}, nil
+	case 1:
+		return docs[0], nil
+	}
+	return nil, ErrMultipleDocuments
+}
+
+// ParseYAMLStream parses a stream of YAML documents separated by `---` markers
+// into a slice of containers, one for each document, following the same rules
+// as ParseYAML.
+func ParseYAMLStream(sample []byte) ([]*Container, error) {
+	p := yamlParser{
+		src:       bytes.ReplaceAll(sample, []byte("\r\n"), []byte("\n")),
+		yamlState: yamlState{line: 1},
+	}
+	return p.parseStream()
+}
+
+// ParseYAMLFile reads a file and parses the contents as a YAML document into a
+// *Container.
+func ParseYAMLFile(path string) (*Container, error) {
+	if path == "" {
+		return nil, ErrInvalidPath
+	}
+	cBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseYAML(cBytes)
+}
+
+// YAML marshals an element to a YAML document in block style. Object keys are
+// sorted and strings are quoted only where they would otherwise be
+// interpreted as a different type. A []byte is written as a !!binary scalar,
+// and values of a type unknown to YAML are converted via their JSON
+// representation.
+func (g *Container) YAML() []byte {
+	var buf bytes.Buffer
+	if err := (&yamlEncoder{buf: &buf}).encodeDocument(g.Data()); err != nil {
+		return []byte("null\n")
+	}
+	return buf.Bytes()
+}
+
+//------------------------------------------------------------------------------
+
+var (
+	yamlIntRegex   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	yamlOctRegex   = regexp.MustCompile(`^0o[0-7]+$`)
+	yamlHexRegex   = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+	yamlFloatRegex = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+// resolveYAMLScalar resolves a plain scalar according to the YAML 1.2 core
+// schema.
+func resolveYAMLScalar(s string) interface{} {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+	switch {
+	case yamlIntRegex.MatchString(s), yamlFloatRegex.MatchString(s):
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case yamlOctRegex.MatchString(s):
+		if i, err := strconv.ParseUint(s[2:], 8, 64); err == nil {
+			return float64(i)
+		}
+	case yamlHexRegex.MatchString(s):
+		if i, err := strconv.ParseUint(s[2:], 16, 64); err == nil {
+			return float64(i)
+		}
+	}
+	return s
+}
+
+// stringifyKey converts a decoded key of any type into an object key, where
+// strings are used verbatim and all other values use their JSON
+// representation.
+func stringifyKey(k interface{}) string {
+	switch t := k.(type) {
+	case string:
+		return t
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(t)
+	case float64:
+		if math.IsInf(t, 0) || math.IsNaN(t) {
+			return strconv.FormatFloat(t, 'g', -1, 64)
+		}
+	case []byte:
+		return string(t)
+	}
+	if b, err := json.Marshal(k); err == nil {
+		return string(b)
+	}
+	return fmt.Sprintf("%v", k)
+}
+
+//------------------------------------------------------------------------------
+
+type yamlState struct {
+	pos, line, lineStart int
+}
+
+// yamlMaxDepth limits the nesting of collections within a document, and
+// yamlMaxAliasNodes limits the total number of nodes that aliases within a
+// document may expand into, which guards against documents such as the
+// "billion laughs" that expand exponentially.
+const (
+	yamlMaxDepth      = 10000
+	yamlMaxAliasNodes = 1000000
+)
+
+type yamlParser struct {
+	src []byte
+	yamlState
+	anchors map[string]interface{}
+
+	depth      int
+	aliasNodes int
+}
+
+type yamlScalar struct {
+	text  string
+	plain bool
+}
+
+func (p *yamlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("yaml: line %v: %v", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *yamlParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *yamlParser) peekAt(n int) byte {
+	if p.pos+n >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos+n]
+}
+
+func (p *yamlParser) peek() byte {
+	return p.peekAt(0)
+}
+
+func (p *yamlParser) col() int {
+	return p.pos - p.lineStart
+}
+
+func (p *yamlParser) advance(n int) {
+	for i := 0; i < n && p.pos < len(p.src); i++ {
+		if p.src[p.pos] == '\n' {
+			p.line++
+			p.lineStart = p.pos + 1
+		}
+		p.pos++
+	}
+}
+
+func isYAMLBlank(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == 0
+}
+
+func isYAMLFlowIndicator(c byte) bool {
+	return c == ',' || c == '[' || c == ']' || c == '{' || c == '}'
+}
+
+func (p *yamlParser) skipInlineSpace() {
+	for p.peek() == ' ' || p.peek() == '\t' {
+		p.advance(1)
+	}
+}
+
+func (p *yamlParser) skipComment() {
+	if p.peek() == '#' {
+		for !p.eof() && p.peek() != '\n' {
+			p.advance(1)
+		}
+	}
+}
+
+// atLineEnd returns true if the rest of the current line is empty or a
+// comment.
+func (p *yamlParser) atLineEnd() bool {
+	return p.eof() || p.peek() == '\n' || p.peek() == '#'
+}
+
+// skipToContent moves to the next character that isn't whitespace or part of
+// a comment, crossing lines. An error is returned if the indentation of the
+// line reached contains tabs.
+func (p *yamlParser) skipToContent() error {
+	for !p.eof() {
+		p.skipInlineSpace()
+		p.skipComment()
+		if p.peek() != '\n' {
+			break
+		}
+		p.advance(1)
+	}
+	if !p.eof() && bytes.IndexByte(p.src[p.lineStart:p.pos], '\t') >= 0 &&
+		len(bytes.TrimLeft(p.src[p.lineStart:p.pos], " \t")) == 0 {
+		return p.errorf("found a tab character in indentation")
+	}
+	return nil
+}
+
+// skipFlowSpace skips whitespace, comments and line breaks within a flow
+// collection.
+func (p *yamlParser) skipFlowSpace() {
+	for !p.eof() {
+		p.skipInlineSpace()
+		p.skipComment()
+		if p.peek() != '\n' {
+			return
+		}
+		p.advance(1)
+	}
+}
+
+func (p *yamlParser) atDocMarker(marker string) bool {
+	return p.col() == 0 &&
+		bytes.HasPrefix(p.src[p.pos:], []byte(marker)) &&
+		isYAMLBlank(p.peekAt(3))
+}
+
+func (p *yamlParser) atDocBoundary() bool {
+	return p.eof() || p.atDocMarker("---") || p.atDocMarker("...")
+}
+
+func (p *yamlParser) atSeqEntry() bool {
+	return p.peek() == '-' && isYAMLBlank(p.peekAt(1))
+}
+
+//------------------------------------------------------------------------------
+
+func (p *yamlParser) parseStream() ([]*Container, error) {
+	var docs []*Container
+	for {
+		if err := p.skipToContent(); err != nil {
+			return nil, err
+		}
+		for !p.eof() && p.col() == 0 && p.peek() == '%' {
+			for !p.eof() && p.peek() != '\n' {
+				p.advance(1)
+			}
+			if err := p.skipToContent(); err != nil {
+				return nil, err
+			}
+		}
+		if p.eof() {
+			return docs, nil
+		}
+		if p.atDocMarker("...") {
+			p.advance(3)
+			continue
+		}
+		if p.atDocMarker("---") {
+			p.advance(3)
+		}
+
+		p.anchors = map[string]interface{}{}
+		p.aliasNodes = 0
+		doc, err := p.parseNode(-1, true, false)
+		if err != nil {
+			return nil, err
+		}
+		if err = p.skipToContent(); err != nil {
+			return nil, err
+		}
+		if !p.atDocBoundary() {
+			return nil, p.errorf("did not find expected end of document")
+		}
+		if p.atDocMarker("...") {
+			p.advance(3)
+		}
+		docs = append(docs, &Container{object: doc})
+	}
+}
+
+// parseNode parses a node that belongs to a parent collection at the
+// indentation indent, where the cursor is placed directly after the indicator
+// that introduced the node. When inlineBlock is true a block collection may
+// begin on the current line, and when seqAtIndent is true a block sequence
+// may begin at the same indentation as the parent.
+func (p *yamlParser) parseNode(indent int, inlineBlock, seqAtIndent bool) (interface{}, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	p.skipInlineSpace()
+
+	var anchor, tag string
+	for {
+		if p.peek() == '&' {
+			anchor = p.readProperty()[1:]
+		} else if p.peek() == '!' {
+			tag = p.readProperty()
+		} else {
+			break
+		}
+		p.skipInlineSpace()
+	}
+
+	var value interface{}
+	var scalar *yamlScalar
+	var err error
+
+	if p.atLineEnd() {
+		if err = p.skipToContent(); err != nil {
+			return nil, err
+		}
+		switch {
+		case p.atDocBoundary():
+		case p.col() > indent:
+			value, scalar, err = p.parseContent(indent, true)
+		case p.col() == indent && seqAtIndent && p.atSeqEntry():
+			value, err = p.parseBlockSeq(p.col())
+		}
+	} else {
+		value, scalar, err = p.parseContent(indent, inlineBlock)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if scalar != nil {
+		if value, err = p.resolveTagged(tag, *scalar); err != nil {
+			return nil, err
+		}
+	} else if value == nil && tag != "" {
+		if value, err = p.resolveTagged(tag, yamlScalar{plain: true}); err != nil {
+			return nil, err
+		}
+	}
+	if anchor != "" {
+		p.anchors[anchor] = value
+	}
+	return value, nil
+}
+
+// parseContent parses the content of a node starting at the cursor. Scalars
+// are returned unresolved so that tags can be applied.
+func (p *yamlParser) parseContent(indent int, block bool) (interface{}, *yamlScalar, error) {
+	switch c := p.peek(); {
+	case c == '*':
+		v, err := p.parseAlias()
+		return v, nil, err
+	case c == '[' || c == '{':
+		v, err := p.parseFlowCollection()
+		return v, nil, err
+	case c == '|' || c == '>':
+		s, err := p.parseBlockScalar(indent)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, &yamlScalar{text: s}, nil
+	case c == '?' && isYAMLBlank(p.peekAt(1)):
+		return nil, nil, p.errorf("complex mapping keys are not supported")
+	case c == '-' && isYAMLBlank(p.peekAt(1)):
+		if !block {
+			return nil, nil, p.errorf("block sequence entries are not allowed in this context")
+		}
+		v, err := p.parseBlockSeq(p.col())
+		return v, nil, err
+	}
+
+	if isKey, err := p.atMappingKey(); err != nil {
+		return nil, nil, err
+	} else if isKey {
+		if !block {
+			return nil, nil, p.errorf("mapping values are not allowed in this context")
+		}
+		v, err := p.parseBlockMapping(p.col())
+		return v, nil, err
+	}
+
+	s, err := p.parseScalar(indent, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &s, nil
+}
+
+func (p *yamlParser) readProperty() string {
+	start := p.pos
+	for !isYAMLBlank(p.peek()) && !isYAMLFlowIndicator(p.peek()) {
+		p.advance(1)
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *yamlParser) parseAlias() (interface{}, error) {
+	p.advance(1)
+	start := p.pos
+	for !isYAMLBlank(p.peek()) && !isYAMLFlowIndicator(p.peek()) {
+		p.advance(1)
+	}
+	name := string(p.src[start:p.pos])
+	v, exists := p.anchors[name]
+	if !exists {
+		return nil, p.errorf("unknown anchor '%v' referenced", name)
+	}
+	p.aliasNodes += countYAMLNodes(v, yamlMaxAliasNodes-p.aliasNodes+1)
+	if p.aliasNodes > yamlMaxAliasNodes {
+		return nil, p.errorf("document contains excessive aliasing")
+	}
+	return deepCopy(v), nil
+}
+
+// countYAMLNodes returns the number of nodes within a value, counting no
+// further than limit.
+func countYAMLNodes(v interface{}, limit int) int {
+	n := 1
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, child := range t {
+			if n >= limit {
+				break
+			}
+			n += countYAMLNodes(child, limit-n)
+		}
+	case []interface{}:
+		for _, child := range t {
+			if n >= limit {
+				break
+			}
+			n += countYAMLNodes(child, limit-n)
+		}
+	}
+	return n
+}
+
+// enter is called when parsing a nested node and returns an error if the
+// maximum depth is exceeded, leave must be called once the node is parsed.
+func (p *yamlParser) enter() error {
+	p.depth++
+	if p.depth > yamlMaxDepth {
+		return p.errorf("exceeded maximum nesting depth")
+	}
+	return nil
+}
+
+func (p *yamlParser) leave() {
+	p.depth--
+}
+
+func (p *yamlParser) resolveTagged(tag string, s yamlScalar) (interface{}, error) {
+	switch tag {
+	case "":
+		if s.plain {
+			return resolveYAMLScalar(s.text), nil
+		}
+		return s.text, nil
+	case "!", "!!str":
+		return s.text, nil
+	case "!!null":
+		return nil, nil
+	case "!!bool":
+		if b, ok := resolveYAMLScalar(s.text).(bool); ok {
+			return b, nil
+		}
+	case "!!int", "!!float":
+		if f, ok := resolveYAMLScalar(s.text).(float64); ok {
+			return f, nil
+		}
+	case "!!binary":
+		b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s.text), ""))
+		if err != nil {
+			return nil, p.errorf("failed to decode binary value: %v", err)
+		}
+		return b, nil
+	default:
+		// Unknown tags are ignored and the value resolved as if untagged.
+		return p.resolveTagged("", s)
+	}
+	return nil, p.errorf("value '%v' could not be resolved as %v", s.text, tag)
+}
+
+//------------------------------------------------------------------------------
+
+// atMappingKey returns true if the current line begins with a mapping key.
+func (p *yamlParser) atMappingKey() (bool, error) {
+	saved := p.yamlState
+	defer func() {
+		p.yamlState = saved
+	}()
+
+	switch p.peek() {
+	case '"', '\'':
+		if _, err := p.parseQuoted(); err != nil {
+			return false, err
+		}
+		if p.line != saved.line {
+			return false, nil
+		}
+		p.skipInlineSpace()
+	default:
+		p.pos = p.scanPlainLine(false)
+	}
+	return p.peek() == ':' && isYAMLBlank(p.peekAt(1)), nil
+}
+
+func (p *yamlParser) parseBlockMapping(col int) (interface{}, error) {
+	m := map[string]interface{}{}
+	var merges []interface{}
+	for {
+		if p.peek() == '?' && isYAMLBlank(p.peekAt(1)) {
+			return nil, p.errorf("complex mapping keys are not supported")
+		}
+
+		var key interface{}
+		isMerge := false
+		switch p.peek() {
+		case '"', '\'':
+			s, err := p.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		default:
+			end := p.scanPlainLine(false)
+			text := string(p.src[p.pos:end])
+			p.pos = end
+			isMerge = text == "<<"
+			key = resolveYAMLScalar(text)
+		}
+		p.skipInlineSpace()
+		if p.peek() != ':' || !isYAMLBlank(p.peekAt(1)) {
+			return nil, p.errorf("could not find expected ':'")
+		}
+		p.advance(1)
+
+		value, err := p.parseNode(col, false, true)
+		if err != nil {
+			return nil, err
+		}
+		if isMerge {
+			merges = append(merges, value)
+		} else {
+			k := stringifyKey(key)
+			if _, exists := m[k]; exists {
+				return nil, p.errorf("mapping key '%v' already defined", k)
+			}
+			m[k] = value
+		}
+
+		if err = p.skipToContent(); err != nil {
+			return nil, err
+		}
+		if p.atDocBoundary() || p.col() < col {
+			break
+		}
+		if p.col() > col {
+			return nil, p.errorf("mapping values are not allowed in this context")
+		}
+	}
+	if err := p.applyMerges(m, merges); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// applyMerges implements merge keys, where fields of the merged mappings are
+// added only when they are not already present.
+func (p *yamlParser) applyMerges(m map[string]interface{}, merges []interface{}) error {
+	var sources []interface{}
+	for _, merge := range merges {
+		if seq, ok := merge.([]interface{}); ok {
+			sources = append(sources, seq...)
+		} else {
+			sources = append(sources, merge)
+		}
+	}
+	for _, source := range sources {
+		sourceMap, ok := source.(map[string]interface{})
+		if !ok {
+			return p.errorf("merge key values must be mappings")
+		}
+		for k, v := range sourceMap {
+			if _, exists := m[k]; !exists {
+				m[k] = v
+			}
+		}
+	}
+	return nil
+}
+
+func (p *yamlParser) parseBlockSeq(col int) (interface{}, error) {
+	seq := []interface{}{}
+	for {
+		p.advance(1)
+		value, err := p.parseNode(col, true, false)
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, value)
+
+		if err = p.skipToContent(); err != nil {
+			return nil, err
+		}
+		if p.atDocBoundary() || p.col() < col {
+			break
+		}
+		if p.col() > col {
+			return nil, p.errorf("did not find expected '-' indicator")
+		}
+		if !p.atSeqEntry() {
+			break
+		}
+	}
+	return seq, nil
+}
+
+//------------------------------------------------------------------------------
+
+// scanPlainLine returns the end offset of a plain scalar on the current line,
+// with trailing whitespace excluded.
+func (p *yamlParser) scanPlainLine(flow bool) int {
+	end := p.pos
+	for i := p.pos; i < len(p.src); i++ {
+		c := p.src[i]
+		if c == '\n' {
+			break
+		}
+		if c == ':' {
+			next := byte(0)
+			if i+1 < len(p.src) {
+				next = p.src[i+1]
+			}
+			if isYAMLBlank(next) || (flow && isYAMLFlowIndicator(next)) {
+				break
+			}
+		}
+		if c == '#' && i > p.pos && (p.src[i-1] == ' ' || p.src[i-1] == '\t') {
+			break
+		}
+		if flow && isYAMLFlowIndicator(c) {
+			break
+		}
+		if c != ' ' && c != '\t' {
+			end = i + 1
+		}
+	}
+	return end
+}
+
+func (p *yamlParser) parseScalar(indent int, flow bool) (yamlScalar, error) {
+	switch p.peek() {
+	case '"', '\'':
+		s, err := p.parseQuoted()
+		return yamlScalar{text: s}, err
+	}
+	if c := p.peek(); strings.IndexByte("#&*!|>%@`,[]{}", c) >= 0 && !(flow && c == '#') {
+		return yamlScalar{}, p.errorf("found character '%c' that cannot start any token", c)
+	}
+
+	end := p.scanPlainLine(flow)
+	text := string(p.src[p.pos:end])
+	p.pos = end
+
+	for {
+		saved := p.yamlState
+		p.skipInlineSpace()
+		if p.peek() != '\n' {
+			p.yamlState = saved
+			break
+		}
+		breaks := 0
+		for p.peek() == '\n' {
+			p.advance(1)
+			breaks++
+			p.skipInlineSpace()
+		}
+		if p.eof() || p.peek() == '#' || p.atDocBoundary() ||
+			(!flow && p.col() <= indent) ||
+			(flow && isYAMLFlowIndicator(p.peek())) {
+			p.yamlState = saved
+			break
+		}
+		lineEnd := p.scanPlainLine(flow)
+		if lineEnd == p.pos {
+			p.yamlState = saved
+			break
+		}
+		if breaks == 1 {
+			text += " "
+		} else {
+			text += strings.Repeat("\n", breaks-1)
+		}
+		text += string(p.src[p.pos:lineEnd])
+		p.pos = lineEnd
+	}
+	return yamlScalar{text: text, plain: true}, nil
+}
+
+// parseQuoted parses a single or double quoted scalar, including line folding.
+func (p *yamlParser) parseQuoted() (string, error) {
+	quote := p.peek()
+	p.advance(1)
+
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("found unexpected end of stream within quoted scalar")
+		}
+		c := p.peek()
+		switch {
+		case c == quote && quote == '\'' && p.peekAt(1) == '\'':
+			b.WriteByte('\'')
+			p.advance(2)
+		case c == quote:
+			p.advance(1)
+			return b.String(), nil
+		case c == '\\' && quote == '"':
+			if p.peekAt(1) == '\n' {
+				p.advance(2)
+				p.skipInlineSpace()
+				continue
+			}
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+		case c == ' ' || c == '\t' || c == '\n':
+			start := p.pos
+			p.skipInlineSpace()
+			if p.peek() != '\n' {
+				b.Write(p.src[start:p.pos])
+				continue
+			}
+			breaks := 0
+			for p.peek() == '\n' {
+				p.advance(1)
+				breaks++
+				p.skipInlineSpace()
+			}
+			if breaks == 1 {
+				b.WriteByte(' ')
+			} else {
+				b.WriteString(strings.Repeat("\n", breaks-1))
+			}
+		default:
+			b.WriteByte(c)
+			p.advance(1)
+		}
+	}
+}
+
+var yamlEscapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n",
+	'v': "\v", 'f': "\f", 'r': "\r", 'e': "\x1b", ' ': " ", '"': "\"",
+	'/': "/", '\\': "\\", 'N': "\u0085", '_': "\u00A0", 'L': "\u2028",
+	'P': "\u2029",
+}
+
+func (p *yamlParser) parseEscape(b *strings.Builder) error {
+	c := p.peekAt(1)
+	if s, exists := yamlEscapes[c]; exists {
+		b.WriteString(s)
+		p.advance(2)
+		return nil
+	}
+	var width int
+	switch c {
+	case 'x':
+		width = 2
+	case 'u':
+		width = 4
+	case 'U':
+		width = 8
+	default:
+		return p.errorf("found unknown escape character '%c'", c)
+	}
+	if p.pos+2+width > len(p.src) {
+		return p.errorf("found unexpected end of stream within escape sequence")
+	}
+	code, err := strconv.ParseUint(string(p.src[p.pos+2:p.pos+2+width]), 16, 32)
+	if err != nil {
+		return p.errorf("did not find expected hexdecimal number")
+	}
+	b.WriteRune(rune(code))
+	p.advance(2 + width)
+	return nil
+}
+
+func (p *yamlParser) parseBlockScalar(indent int) (string, error) {
+	literal := p.peek() == '|'
+	p.advance(1)
+
+	chomp := byte(0)
+	explicitIndent := 0
+	for i := 0; i < 2; i++ {
+		switch c := p.peek(); {
+		case c == '+' || c == '-':
+			chomp = c
+			p.advance(1)
+		case c >= '1' && c <= '9':
+			explicitIndent = int(c - '0')
+			p.advance(1)
+		}
+	}
+	p.skipInlineSpace()
+	p.skipComment()
+	if !p.eof() && p.peek() != '\n' {
+		return "", p.errorf("did not find expected comment or line break")
+	}
+	p.advance(1)
+
+	blockIndent := indent + explicitIndent
+	if explicitIndent == 0 {
+		// Detect the indentation from the first non-empty line.
+		blockIndent = -1
+		for i := p.pos; i < len(p.src); {
+			j := i
+			for j < len(p.src) && p.src[j] == ' ' {
+				j++
+			}
+			if j < len(p.src) && p.src[j] != '\n' {
+				blockIndent = j - i
+				break
+			}
+			i = j + 1
+		}
+		if blockIndent <= indent {
+			blockIndent = indent + 1
+		}
+	}
+
+	var lines []string
+	for !p.eof() {
+		if p.atDocBoundary() {
+			break
+		}
+		lineStart := p.yamlState
+		spaces := 0
+		for p.peek() == ' ' && spaces < blockIndent {
+			p.advance(1)
+			spaces++
+		}
+		if p.eof() || p.peek() == '\n' {
+			lines = append(lines, "")
+			p.advance(1)
+			continue
+		}
+		if spaces < blockIndent {
+			p.yamlState = lineStart
+			break
+		}
+		start := p.pos
+		for !p.eof() && p.peek() != '\n' {
+			p.advance(1)
+		}
+		lines = append(lines, string(p.src[start:p.pos]))
+		p.advance(1)
+	}
+
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+
+	var b strings.Builder
+	leading := 0
+	for leading < len(lines) && lines[leading] == "" {
+		leading++
+	}
+	b.WriteString(strings.Repeat("\n", leading))
+	lines = lines[leading:]
+
+	moreIndented := func(l string) bool {
+		return l != "" && (l[0] == ' ' || l[0] == '\t')
+	}
+	for i := 0; i < len(lines); {
+		b.WriteString(lines[i])
+		j, empty := i+1, 0
+		for j < len(lines) && lines[j] == "" {
+			j++
+			empty++
+		}
+		if j >= len(lines) {
+			break
+		}
+		if literal || moreIndented(lines[i]) || moreIndented(lines[j]) {
+			b.WriteString(strings.Repeat("\n", empty+1))
+		} else if empty == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteString(strings.Repeat("\n", empty))
+		}
+		i = j
+	}
+
+	switch {
+	case chomp == '-':
+	case len(lines) == 0 && chomp != '+':
+	case chomp == '+':
+		b.WriteString(strings.Repeat("\n", trailing+1))
+	default:
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+//------------------------------------------------------------------------------
+
+func (p *yamlParser) parseFlowCollection() (interface{}, error) {
+	isSeq := p.peek() == '['
+	closing := byte('}')
+	if isSeq {
+		closing = ']'
+	}
+	p.advance(1)
+
+	seq := []interface{}{}
+	m := map[string]interface{}{}
+	for {
+		p.skipFlowSpace()
+		if p.eof() {
+			return nil, p.errorf("did not find expected '%c'", closing)
+		}
+		if p.peek() == closing {
+			p.advance(1)
+			break
+		}
+
+		key, err := p.parseFlowNode()
+		if err != nil {
+			return nil, err
+		}
+		p.skipFlowSpace()
+
+		var value interface{}
+		hasValue := false
+		if p.peek() == ':' {
+			p.advance(1)
+			hasValue = true
+			p.skipFlowSpace()
+			if p.peek() != ',' && p.peek() != closing {
+				if value, err = p.parseFlowNode(); err != nil {
+					return nil, err
+				}
+				p.skipFlowSpace()
+			}
+		}
+
+		switch {
+		case !isSeq:
+			m[stringifyKey(key)] = value
+		case hasValue:
+			seq = append(seq, map[string]interface{}{stringifyKey(key): value})
+		default:
+			seq = append(seq, key)
+		}
+
+		if p.peek() == ',' {
+			p.advance(1)
+		} else if p.peek() != closing {
+			return nil, p.errorf("did not find expected ',' or '%c'", closing)
+		}
+	}
+	if isSeq {
+		return seq, nil
+	}
+	return m, nil
+}
+
+func (p *yamlParser) parseFlowNode() (interface{}, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	var anchor, tag string
+	for {
+		if p.peek() == '&' {
+			anchor = p.readProperty()[1:]
+		} else if p.peek() == '!' {
+			tag = p.readProperty()
+		} else {
+			break
+		}
+		p.skipFlowSpace()
+	}
+
+	var value interface{}
+	var err error
+	switch c := p.peek(); {
+	case c == '*':
+		value, err = p.parseAlias()
+	case c == '[' || c == '{':
+		value, err = p.parseFlowCollection()
+	case c == ',' || c == ']' || c == '}' || c == ':':
+		value, err = p.resolveTagged(tag, yamlScalar{plain: true})
+	default:
+		var s yamlScalar
+		if s, err = p.parseScalar(-1, true); err == nil {
+			value, err = p.resolveTagged(tag, s)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if anchor != "" {
+		p.anchors[anchor] = value
+	}
+	return value, nil
+}
+
+//------------------------------------------------------------------------------
+
+type yamlEncoder struct {
+	buf *bytes.Buffer
+}
+
+func (e *yamlEncoder) encodeDocument(v interface{}) error {
+	v, err := e.normalize(v)
+	if err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(t) > 0 {
+			return e.encodeMapping(t, 0, false)
+		}
+	case []interface{}:
+		if len(t) > 0 {
+			return e.encodeSequence(t, 0, false)
+		}
+	}
+	return e.encodeScalarLine(v)
+}
+
+func (e *yamlEncoder) writeIndent(indent int) {
+	for i := 0; i < indent; i++ {
+		e.buf.WriteByte(' ')
+	}
+}
+
+// encodeMapping writes a block mapping at an indentation. When inline is true
+// the first key is written at the current cursor, which is assumed to already
+// be at the given indentation.
+func (e *yamlEncoder) encodeMapping(m map[string]interface{}, indent int, inline bool) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for i, k := range keys {
+		if i > 0 || !inline {
+			e.writeIndent(indent)
+		}
+		e.buf.WriteString(yamlQuote(k))
+		e.buf.WriteByte(':')
+
+		v, err := e.normalize(m[k])
+		if err != nil {
+			return err
+		}
+		switch t := v.(type) {
+		case map[string]interface{}:
+			if len(t) > 0 {
+				e.buf.WriteByte('\n')
+				if err := e.encodeMapping(t, indent+2, false); err != nil {
+					return err
+				}
+				continue
+			}
+		case []interface{}:
+			if len(t) > 0 {
+				e.buf.WriteByte('\n')
+				if err := e.encodeSequence(t, indent+2, false); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		e.buf.WriteByte(' ')
+		if err := e.encodeScalarLine(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *yamlEncoder) encodeSequence(s []interface{}, indent int, inline bool) error {
+	for i, ele := range s {
+		if i > 0 || !inline {
+			e.writeIndent(indent)
+		}
+		e.buf.WriteString("- ")
+
+		v, err := e.normalize(ele)
+		if err != nil {
+			return err
+		}
+		switch t := v.(type) {
+		case map[string]interface{}:
+			if len(t) > 0 {
+				if err := e.encodeMapping(t, indent+2, true); err != nil {
+					return err
+				}
+				continue
+			}
+		case []interface{}:
+			if len(t) > 0 {
+				if err := e.encodeSequence(t, indent+2, true); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := e.encodeScalarLine(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalize converts values of types unknown to the encoder into their generic
+// JSON representation.
+func (e *yamlEncoder) normalize(v interface{}) (interface{}, error) {
+	switch v.(type) {
+	case nil, bool, string, []byte, map[string]interface{}, []interface{}:
+		return v, nil
+	case float64, float32:
+		return v, nil
+	}
+	if _, isNum := toRat(v); isNum {
+		return v, nil
+	}
+	return toGeneric(v)
+}
+
+func (e *yamlEncoder) encodeScalarLine(v interface{}) error {
+	v, err := e.normalize(v)
+	if err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case nil:
+		e.buf.WriteString("null")
+	case bool:
+		e.buf.WriteString(strconv.FormatBool(t))
+	case string:
+		e.buf.WriteString(yamlQuote(t))
+	case []byte:
+		e.buf.WriteString("!!binary ")
+		if len(t) == 0 {
+			e.buf.WriteString(`""`)
+		} else {
+			e.buf.WriteString(base64.StdEncoding.EncodeToString(t))
+		}
+	case map[string]interface{}:
+		e.buf.WriteString("{}")
+	case []interface{}:
+		e.buf.WriteString("[]")
+	case float32:
+		return e.encodeScalarLine(float64(t))
+	case float64:
+		switch {
+		case math.IsNaN(t):
+			e.buf.WriteString(".nan")
+		case math.IsInf(t, 1):
+			e.buf.WriteString(".inf")
+		case math.IsInf(t, -1):
+			e.buf.WriteString("-.inf")
+		default:
+			b, err := json.Marshal(t)
+			if err != nil {
+				return err
+			}
+			e.buf.Write(b)
+		}
+	default:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		e.buf.Write(b)
+	}
+	e.buf.WriteByte('\n')
+	return nil
+}
+
+// yamlQuote returns a string as a plain scalar when it can be represented as
+// one, otherwise as a double quoted scalar.
+func yamlQuote(s string) string {
+	if yamlNeedsQuotes(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func yamlNeedsQuotes(s string) bool {
+	if s == "" {
+		return true
+	}
+	if _, isStr := resolveYAMLScalar(s).(string); !isStr {
+		return true
+	}
+	if strings.IndexByte("-?:,[]{}#&*!|>'\"%@` \t", s[0]) >= 0 {
+		return true
+	}
+	if last := s[len(s)-1]; last == ' ' || last == '\t' || last == ':' {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasPrefix(s, "...") {
+		return true
+	}
+	if !utf8.ValidString(s) {
+		return true
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) || r == '\uFEFF' {
+			return true
+		}
+	}
+	return false
+}
+
+//------------------------------------------------------------------------------
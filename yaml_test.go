@@ -0,0 +1,258 @@
+package gabs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	type testCase struct {
+		input  string
+		output string
+	}
+	tests := []testCase{
+		{
+			input:  "foo: bar\nbaz: 10\n",
+			output: `{"baz":10,"foo":"bar"}`,
+		},
+		{
+			input:  "# comment\nfoo: # another\n  bar: [1, 2.5, {a: b}] # trailing\n",
+			output: `{"foo":{"bar":[1,2.5,{"a":"b"}]}}`,
+		},
+		{
+			input:  "a: ~\nb: null\nc: true\nd: False\ne: 0x1F\nf: 0o17\ng: 1e3\nh: -.5\ni: 1.2.3\nj: \"true\"\n",
+			output: `{"a":null,"b":null,"c":true,"d":false,"e":31,"f":15,"g":1000,"h":-0.5,"i":"1.2.3","j":"true"}`,
+		},
+		{
+			input:  "list:\n- a\n- b: 1\n  c: 2\n- - x\n  - y\n-\nnext: 1\n",
+			output: `{"list":["a",{"b":1,"c":2},["x","y"],null],"next":1}`,
+		},
+		{
+			input:  "list:\n  - 1\n  -   - 2\n      - 3\n",
+			output: `{"list":[1,[2,3]]}`,
+		},
+		{
+			input:  "a: \"esc\\t\\u00e9\\x41\"\nb: 'it''s'\nc: \"folded\n  line\"\nd: plain\n  continued\n\n  paragraph\n",
+			output: `{"a":"esc\téA","b":"it's","c":"folded line","d":"plain continued\nparagraph"}`,
+		},
+		{
+			input:  "lit: |\n  one\n   two\n\n  three\nfold: >\n  one\n  two\n\n  three\n   four\nstrip: |-\n  x\n\nkeep: |+\n  x\n\nlast: 1\n",
+			output: `{"fold":"one two\nthree\n four\n","keep":"x\n\n","last":1,"lit":"one\n two\n\nthree\n","strip":"x"}`,
+		},
+		{
+			input:  "base: &base\n  a: 1\n  b: 2\nderived:\n  <<: *base\n  b: 3\ncopy: *base\n",
+			output: `{"base":{"a":1,"b":2},"copy":{"a":1,"b":2},"derived":{"a":1,"b":3}}`,
+		},
+		{
+			input:  "1: int\n2.50: float\ntrue: bool\n~: null\n\"quoted\": str\n",
+			output: `{"1":"int","2.5":"float","null":null,"quoted":"str","true":"bool"}`,
+		},
+		{
+			input:  "- !!str 10\n- !!int \"10\"\n- !custom value\n- !!binary aGVsbG8=\n",
+			output: `["10",10,"value","aGVsbG8="]`,
+		},
+		{
+			input:  "{a: [1, 2,\n  3], b: {c: d}, e}\n",
+			output: `{"a":[1,2,3],"b":{"c":"d"},"e":null}`,
+		},
+		{
+			input:  "[a: 1, b]\n",
+			output: `[{"a":1},"b"]`,
+		},
+		{
+			input:  "%YAML 1.2\n---\nurl: http://example.com:8080/path\ntime: 2023-01-01T10:00:00Z\n...\n",
+			output: `{"time":"2023-01-01T10:00:00Z","url":"http://example.com:8080/path"}`,
+		},
+		{
+			input:  "",
+			output: `null`,
+		},
+		{
+			input:  "just a string\n",
+			output: `"just a string"`,
+		},
+	}
+
+	for i, test := range tests {
+		c, err := ParseYAML([]byte(test.input))
+		if err != nil {
+			t.Errorf("[%d] Failed to parse: %v", i, err)
+			continue
+		}
+		if exp, act := test.output, c.String(); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []string{
+		"a: b: c\n",
+		"a:\n  b: 1\n c: 2\n",
+		"a: 1\na: 2\n",
+		"a: *nope\n",
+		"a: \"unterminated\n",
+		"a: [1, 2\n",
+		"? complex\n: key\n",
+		"a:\n\tb: 1\n",
+		"- a\nb: 1\n",
+	}
+	for i, test := range tests {
+		if _, err := ParseYAML([]byte(test)); err == nil {
+			t.Errorf("[%d] Expected error for input: %q", i, test)
+		}
+	}
+}
+
+func TestParseYAMLLimits(t *testing.T) {
+	var laughs strings.Builder
+	laughs.WriteString("a: &a [\"lol\",\"lol\",\"lol\",\"lol\",\"lol\",\"lol\",\"lol\",\"lol\",\"lol\"]\n")
+	prev := "a"
+	for _, name := range []string{"b", "c", "d", "e", "f", "g", "h", "i"} {
+		laughs.WriteString(name + ": &" + name + " [")
+		for j := 0; j < 9; j++ {
+			if j > 0 {
+				laughs.WriteString(",")
+			}
+			laughs.WriteString("*" + prev)
+		}
+		laughs.WriteString("]\n")
+		prev = name
+	}
+	if _, err := ParseYAML([]byte(laughs.String())); err == nil || !strings.Contains(err.Error(), "excessive aliasing") {
+		t.Errorf("Expected aliasing error, received: %v", err)
+	}
+
+	if _, err := ParseYAML([]byte("a: &a [1, 2]\nb: [*a, *a, *a]\n")); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, err := ParseYAML([]byte(strings.Repeat("[", 3000000))); err == nil || !strings.Contains(err.Error(), "maximum nesting depth") {
+		t.Errorf("Expected depth error, received: %v", err)
+	}
+	if _, err := ParseYAML([]byte(strings.Repeat("- ", 20000) + "a\n")); err == nil || !strings.Contains(err.Error(), "maximum nesting depth") {
+		t.Errorf("Expected depth error, received: %v", err)
+	}
+	if _, err := ParseYAML([]byte(strings.Repeat("[", 100) + strings.Repeat("]", 100))); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestParseYAMLStream(t *testing.T) {
+	input := "---\na: 1\n---\n- b\n...\n--- c\n---\n"
+	docs, err := ParseYAMLStream([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{`{"a":1}`, `["b"]`, `"c"`, `null`}
+	if len(docs) != len(exp) {
+		t.Fatalf("Wrong count of documents: %v != %v", len(docs), len(exp))
+	}
+	for i, d := range docs {
+		if act := d.String(); exp[i] != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp[i])
+		}
+	}
+
+	if _, err = ParseYAML([]byte(input)); err != ErrMultipleDocuments {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestYAMLEncode(t *testing.T) {
+	c, err := ParseJSON([]byte(`{
+		"str": "hello world",
+		"quoted": ["true", "10", "", "- dash", "a: b", "multi\nline", " padded", "null"],
+		"num": 10.5,
+		"bool": false,
+		"null": null,
+		"empty": {"obj": {}, "arr": []},
+		"nested": [{"a": 1, "b": [1, 2]}, [3, [4]]]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `bool: false
+empty:
+  arr: []
+  obj: {}
+nested:
+  - a: 1
+    b:
+      - 1
+      - 2
+  - - 3
+    - - 4
+"null": null
+num: 10.5
+quoted:
+  - "true"
+  - "10"
+  - ""
+  - "- dash"
+  - "a: b"
+  - "multi\nline"
+  - " padded"
+  - "null"
+str: hello world
+`
+	if act := string(c.YAML()); exp != act {
+		t.Errorf("Wrong result: %v != %v", act, exp)
+	}
+
+	roundTrip, err := ParseYAML(c.YAML())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(c, roundTrip) {
+		t.Errorf("Round trip mismatch: %v != %v", roundTrip, c)
+	}
+}
+
+func TestYAMLBinaryRoundTrip(t *testing.T) {
+	c := Wrap(map[string]interface{}{
+		"bin":   []byte("hello"),
+		"empty": []byte{},
+		"list":  []interface{}{[]byte{0, 1, 255}, "text"},
+		"obj":   map[string]interface{}{"raw": []byte("\x00nested")},
+	})
+	roundTrip, err := ParseYAML(c.YAML())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"bin", "empty", "list.0", "obj.raw"} {
+		exp, act := c.Path(path).Data(), roundTrip.Path(path).Data()
+		if _, isBytes := act.([]byte); !isBytes {
+			t.Errorf("Wrong type at %v: %T", path, act)
+		} else if !Equal(Wrap(exp), Wrap(act)) {
+			t.Errorf("Wrong result at %v: %v != %v", path, act, exp)
+		}
+	}
+	if !Equal(c, roundTrip) {
+		t.Errorf("Round trip mismatch: %v != %v", roundTrip, c)
+	}
+}
+
+func TestYAMLEncodeScalars(t *testing.T) {
+	type testCase struct {
+		input  interface{}
+		output string
+	}
+	tests := []testCase{
+		{input: nil, output: "null\n"},
+		{input: "foo", output: "foo\n"},
+		{input: 5, output: "5\n"},
+		{input: []byte("hello"), output: "!!binary aGVsbG8=\n"},
+		{input: []byte{}, output: "!!binary \"\"\n"},
+		{input: map[string]interface{}{}, output: "{}\n"},
+		{input: struct {
+			A string `json:"a"`
+		}{A: "b"}, output: "a: b\n"},
+	}
+	for i, test := range tests {
+		if exp, act := test.output, string(Wrap(test.input).YAML()); exp != act {
+			t.Errorf("[%d] Wrong result: %v != %v", i, act, exp)
+		}
+	}
+}